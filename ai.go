@@ -0,0 +1,91 @@
+package useragent
+
+import (
+	"strings"
+)
+
+// AIPurpose is what an AI agent fetches pages for, which usually decides
+// whether a site wants to allow it.
+type AIPurpose string
+
+// AI purposes reported by AIAgent.
+const (
+	// AIPurposeTraining is a crawler collecting data to train models.
+	AIPurposeTraining AIPurpose = "training"
+	// AIPurposeSearch is a crawler building a search index for an AI
+	// assistant.
+	AIPurposeSearch AIPurpose = "search"
+	// AIPurposeUserFetch is a fetcher that loads a page because a user asked
+	// an AI assistant about it.
+	AIPurposeUserFetch AIPurpose = "user_fetch"
+)
+
+// String returns the AI purpose as a string.
+func (p AIPurpose) String() string {
+	return string(p)
+}
+
+// AllAIPurposes returns every AI purpose in the catalog.
+func AllAIPurposes() []AIPurpose {
+	return []AIPurpose{
+		AIPurposeTraining,
+		AIPurposeSearch,
+		AIPurposeUserFetch,
+	}
+}
+
+// AIAgent describes an AI crawler or fetcher.
+type AIAgent struct {
+	// Name is the name of the agent, e.g. "GPTBot".
+	Name string
+	// Operator is the company running the agent, e.g. "OpenAI".
+	Operator string
+	Purpose  AIPurpose
+	// RobotsToken is the robots.txt product token the agent obeys.
+	RobotsToken string
+	// RobotsOnly is true for tokens that only exist in robots.txt, such as
+	// Google-Extended, and never appear in a user agent.
+	RobotsOnly bool
+}
+
+// AIAgents returns the catalog of AI agents.
+func AIAgents() []AIAgent {
+	return append([]AIAgent(nil), aiAgents[:]...)
+}
+
+// AIAgent returns the AI agent that sent the user agent, if it is a known AI
+// crawler or fetcher.
+func (ua *UserAgent) AIAgent() (AIAgent, bool) {
+	token := ua.RobotsToken()
+	if token == "" {
+		return AIAgent{}, false
+	}
+
+	for _, agent := range aiAgents {
+		if strings.EqualFold(agent.RobotsToken, token) {
+			return agent, true
+		}
+	}
+
+	return AIAgent{}, false
+}
+
+var aiAgents = [...]AIAgent{
+	// Training crawlers
+	{Name: "GPTBot", Operator: "OpenAI", Purpose: AIPurposeTraining, RobotsToken: "GPTBot"},
+	{Name: "ClaudeBot", Operator: "Anthropic", Purpose: AIPurposeTraining, RobotsToken: "ClaudeBot"},
+	{Name: "CCBot", Operator: "Common Crawl", Purpose: AIPurposeTraining, RobotsToken: "CCBot"},
+	{Name: "Google-Extended", Operator: "Google", Purpose: AIPurposeTraining, RobotsToken: "Google-Extended", RobotsOnly: true},
+	{Name: "Applebot-Extended", Operator: "Apple", Purpose: AIPurposeTraining, RobotsToken: "Applebot-Extended", RobotsOnly: true},
+	{Name: "Bytespider", Operator: "ByteDance", Purpose: AIPurposeTraining, RobotsToken: "Bytespider"},
+	{Name: "Meta-ExternalAgent", Operator: "Meta", Purpose: AIPurposeTraining, RobotsToken: "meta-externalagent"},
+	// Search indexers
+	{Name: "OAI-SearchBot", Operator: "OpenAI", Purpose: AIPurposeSearch, RobotsToken: "OAI-SearchBot"},
+	{Name: "Claude-SearchBot", Operator: "Anthropic", Purpose: AIPurposeSearch, RobotsToken: "Claude-SearchBot"},
+	{Name: "PerplexityBot", Operator: "Perplexity", Purpose: AIPurposeSearch, RobotsToken: "PerplexityBot"},
+	// User triggered fetchers
+	{Name: "ChatGPT-User", Operator: "OpenAI", Purpose: AIPurposeUserFetch, RobotsToken: "ChatGPT-User"},
+	{Name: "Claude-User", Operator: "Anthropic", Purpose: AIPurposeUserFetch, RobotsToken: "Claude-User"},
+	{Name: "Perplexity-User", Operator: "Perplexity", Purpose: AIPurposeUserFetch, RobotsToken: "Perplexity-User"},
+	{Name: "Meta-ExternalFetcher", Operator: "Meta", Purpose: AIPurposeUserFetch, RobotsToken: "meta-externalfetcher"},
+}
@@ -0,0 +1,165 @@
+package useragent
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAIAgent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		agent     string
+		operator  string
+		purpose   AIPurpose
+	}{
+		{
+			name:      "GPTBot",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)",
+			agent:     "GPTBot",
+			operator:  "OpenAI",
+			purpose:   AIPurposeTraining,
+		},
+		{
+			name:      "OAI-SearchBot",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko); compatible; OAI-SearchBot/1.0; +https://openai.com/searchbot",
+			agent:     "OAI-SearchBot",
+			operator:  "OpenAI",
+			purpose:   AIPurposeSearch,
+		},
+		{
+			name:      "ChatGPT-User",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ChatGPT-User/1.0; +https://openai.com/bot)",
+			agent:     "ChatGPT-User",
+			operator:  "OpenAI",
+			purpose:   AIPurposeUserFetch,
+		},
+		{
+			name:      "ClaudeBot",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ClaudeBot/1.0; +claudebot@anthropic.com)",
+			agent:     "ClaudeBot",
+			operator:  "Anthropic",
+			purpose:   AIPurposeTraining,
+		},
+		{
+			name:      "Claude-User",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Claude-User/1.0; +Claude-User@anthropic.com)",
+			agent:     "Claude-User",
+			operator:  "Anthropic",
+			purpose:   AIPurposeUserFetch,
+		},
+		{
+			name:      "PerplexityBot",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; PerplexityBot/1.0; +https://perplexity.ai/perplexitybot)",
+			agent:     "PerplexityBot",
+			operator:  "Perplexity",
+			purpose:   AIPurposeSearch,
+		},
+		{
+			name:      "Perplexity-User",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Perplexity-User/1.0; +https://perplexity.ai/perplexity-user)",
+			agent:     "Perplexity-User",
+			operator:  "Perplexity",
+			purpose:   AIPurposeUserFetch,
+		},
+		{
+			name:      "CCBot",
+			userAgent: "CCBot/2.0 (https://commoncrawl.org/faq/)",
+			agent:     "CCBot",
+			operator:  "Common Crawl",
+			purpose:   AIPurposeTraining,
+		},
+		{
+			name:      "Bytespider",
+			userAgent: "Mozilla/5.0 (Linux; Android 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36 (compatible; Bytespider; spider-feedback@bytedance.com)",
+			agent:     "Bytespider",
+			operator:  "ByteDance",
+			purpose:   AIPurposeTraining,
+		},
+		{
+			name:      "Meta-ExternalAgent",
+			userAgent: "meta-externalagent/1.1 (+https://developers.facebook.com/docs/sharing/webmasters/crawler)",
+			agent:     "Meta-ExternalAgent",
+			operator:  "Meta",
+			purpose:   AIPurposeTraining,
+		},
+		{
+			name:      "Googlebot is not an AI agent",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		},
+		{
+			name:      "Browser",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+
+			agent, ok := ua.AIAgent()
+			if ok != (tc.agent != "") {
+				t.Fatalf("expected AIAgent() found %v, but got %v (%+v)", tc.agent != "", ok, agent)
+			}
+
+			if agent.Name != tc.agent || agent.Operator != tc.operator || agent.Purpose != tc.purpose {
+				t.Errorf("expected %s by %s for %s, but got %+v", tc.agent, tc.operator, tc.purpose, agent)
+			}
+
+			if ok && ua.BotCategory() != BotAI {
+				t.Errorf("expected bot category %q, but got %q", BotAI, ua.BotCategory())
+			}
+		})
+	}
+}
+
+func TestAIAgentsCoverRules(t *testing.T) {
+	t.Parallel()
+
+	for _, bot := range KnownBots() {
+		if bot.Category != BotAI || len(bot.Tokens) == 0 {
+			continue
+		}
+
+		found := slices.ContainsFunc(AIAgents(), func(agent AIAgent) bool {
+			return strings.EqualFold(agent.RobotsToken, bot.Tokens[0])
+		})
+		if !found {
+			t.Errorf("expected AI bot %q to be in the AI agent catalog", bot.Name)
+		}
+	}
+
+	for _, agent := range AIAgents() {
+		if !slices.Contains(AllAIPurposes(), agent.Purpose) {
+			t.Errorf("expected AI agent %q to have a known purpose, but got %q", agent.Name, agent.Purpose)
+		}
+	}
+}
+
+func TestRobotsPolicyAIPurposes(t *testing.T) {
+	t.Parallel()
+
+	policy := &RobotsPolicy{
+		AIPurposes: map[AIPurpose]RobotsRule{
+			AIPurposeTraining: {Deny: true},
+		},
+	}
+
+	robots := ParseRobots([]byte(policy.Generate()))
+
+	for _, agent := range AIAgents() {
+		group, ok := robots.group(agent.RobotsToken)
+		if !ok {
+			group, _ = robots.group("*")
+		}
+
+		if allowed := group.Allowed("/"); allowed == (agent.Purpose == AIPurposeTraining) {
+			t.Errorf("expected %s (%s) to be allowed %v", agent.Name, agent.Purpose, !allowed)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package useragent
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AnomalyKind classifies an inconsistency found in a user agent.
+type AnomalyKind string
+
+// Anomaly kinds reported by Anomalies.
+const (
+	// AnomalyObsoleteOS is a browser version that was never released for the
+	// operating system, such as Chrome 120 on Windows XP.
+	AnomalyObsoleteOS AnomalyKind = "obsolete_os"
+	// AnomalyUnsupportedOS is a browser that is not available on the
+	// operating system at all, such as a modern Safari on Windows.
+	AnomalyUnsupportedOS AnomalyKind = "unsupported_os"
+	// AnomalyPlatformMismatch is a user agent that claims two incompatible
+	// platforms at once, such as an iPhone with a Win64 token.
+	AnomalyPlatformMismatch AnomalyKind = "platform_mismatch"
+	// AnomalyMissingToken is a browser that lacks a token the real browser
+	// always sends, such as Edge without the Chrome token.
+	AnomalyMissingToken AnomalyKind = "missing_token"
+	// AnomalyVersionMismatch is a pair of tokens whose versions never differ
+	// in the real browser, such as Edg/120 next to Chrome/90.
+	AnomalyVersionMismatch AnomalyKind = "version_mismatch"
+	// AnomalyEngineMismatch is a browser claiming a rendering engine it does
+	// not use, such as Firefox with AppleWebKit outside of iOS.
+	AnomalyEngineMismatch AnomalyKind = "engine_mismatch"
+)
+
+// String returns the anomaly kind as a string.
+func (k AnomalyKind) String() string {
+	return string(k)
+}
+
+// Anomaly is an inconsistency found in a user agent, which usually means the
+// user agent was made up or altered by hand.
+type Anomaly struct {
+	Kind   AnomalyKind
+	Reason string
+}
+
+// String returns the anomaly as "kind: reason".
+func (a Anomaly) String() string {
+	return a.Kind.String() + ": " + a.Reason
+}
+
+// anomalyRule checks a parsed user agent for a single kind of inconsistency.
+type anomalyRule struct {
+	kind  AnomalyKind
+	check func(ua *UserAgent) (string, bool)
+}
+
+// lastRelease holds the last major version of a browser released for a device.
+type lastRelease struct {
+	device  string
+	browser BrowserFamily
+	major   int
+}
+
+// Anomalies runs a set of consistency rules over the parsed browser, version,
+// operating system and device and returns every inconsistency found. A user
+// agent sent by a real browser has no anomalies.
+func (ua *UserAgent) Anomalies() []Anomaly {
+	var anomalies []Anomaly
+
+	for _, rule := range anomalyRules {
+		if reason, found := rule.check(ua); found {
+			anomalies = append(anomalies, Anomaly{Kind: rule.kind, Reason: reason})
+		}
+	}
+
+	return anomalies
+}
+
+// IsAnomalous returns true if the user agent has any anomalies.
+func (ua *UserAgent) IsAnomalous() bool {
+	return len(ua.Anomalies()) > 0
+}
+
+// checkObsoleteOS reports browser versions released after the operating
+// system was dropped.
+func checkObsoleteOS(ua *UserAgent) (string, bool) {
+	if ua.browserVersion.IsZero() {
+		return "", false
+	}
+
+	major := ua.browserVersion.Major()
+
+	for _, r := range lastReleases {
+		if r.device == ua.device && r.browser == ua.browserFamily && major > r.major {
+			return ua.browser + " " + strconv.Itoa(major) + " was never released for " + ua.device +
+				" (last version was " + strconv.Itoa(r.major) + ")", true
+		}
+	}
+
+	return "", false
+}
+
+// checkUnsupportedOS reports Safari on platforms Apple never shipped it for.
+// Safari 5 and older existed on Windows, and old Android browsers send a
+// Safari token with a low version.
+func checkUnsupportedOS(ua *UserAgent) (string, bool) {
+	if ua.browserFamily != BrowserSafari {
+		return "", false
+	}
+
+	if ua.operatingSystem != OSWindows && ua.operatingSystem != OSAndroid {
+		return "", false
+	}
+
+	// Internet Explorer on Windows Phone claims Safari without a version
+	if windowsPhoneRegEx.MatchString(ua.userAgent) {
+		return "", false
+	}
+
+	// A Safari without a version cannot claim to be one of the old ones
+	if ua.browserVersion.IsZero() {
+		return "Safari is not available on " + ua.operatingSystem.String(), true
+	}
+
+	if ua.browserVersion.Major() <= 5 {
+		return "", false
+	}
+
+	return "Safari " + ua.browserVersion.String() + " is not available on " + ua.operatingSystem.String(), true
+}
+
+// checkPlatformMismatch reports user agents that claim more than one platform.
+// Windows Phone is excluded since it deliberately claims Android and iPhone.
+func checkPlatformMismatch(ua *UserAgent) (string, bool) {
+	if windowsPhoneRegEx.MatchString(ua.userAgent) {
+		return "", false
+	}
+
+	hasIOS := iosPlatformRegEx.MatchString(ua.userAgent)
+	hasWindows := windowsPlatformRegEx.MatchString(ua.userAgent)
+	hasAndroid := androidPlatformRegEx.MatchString(ua.userAgent)
+	hasMac := macPlatformRegEx.MatchString(ua.userAgent)
+
+	switch {
+	case hasIOS && hasWindows:
+		return "iOS device with Windows platform tokens", true
+	case hasIOS && hasAndroid:
+		return "iOS device with Android platform tokens", true
+	case hasAndroid && hasWindows:
+		return "Android device with Windows platform tokens", true
+	case hasMac && hasWindows:
+		return "Macintosh with Windows platform tokens", true
+	}
+
+	return "", false
+}
+
+// checkMissingToken reports Chromium based browsers without the tokens every
+// Chromium build sends. On iOS all browsers use WebKit and send no Chrome token.
+func checkMissingToken(ua *UserAgent) (string, bool) {
+	if !isChromiumBased(ua) || ua.operatingSystem == OSIOS {
+		return "", false
+	}
+
+	var missing []string
+
+	userAgent := strings.ToLower(ua.userAgent)
+	for _, token := range chromiumTokens {
+		if !strings.Contains(userAgent, strings.ToLower(token)) {
+			missing = append(missing, strings.TrimSuffix(token, "/"))
+		}
+	}
+
+	if len(missing) == 0 {
+		return "", false
+	}
+
+	return ua.browser + " is missing the " + strings.Join(missing, ", ") + " token(s) Chromium always sends", true
+}
+
+// checkVersionMismatch reports Chromium based Edge whose major version differs
+// from the Chrome token, which the real browser always keeps in sync.
+func checkVersionMismatch(ua *UserAgent) (string, bool) {
+	if ua.browserFamily != BrowserEdge || ua.operatingSystem == OSIOS {
+		return "", false
+	}
+
+	edgeMajor := ua.browserVersion.Major()
+	chromeMajor := majorVersion(firstSubmatch(browserVersionRegEx[BrowserChrome], ua.userAgent))
+
+	if edgeMajor < 79 || chromeMajor < 0 || edgeMajor == chromeMajor {
+		return "", false
+	}
+
+	return "Edge " + strconv.Itoa(edgeMajor) + " with Chrome " + strconv.Itoa(chromeMajor) + " token", true
+}
+
+// checkEngineMismatch reports Gecko based browsers that claim WebKit. Firefox
+// on iOS is excluded since it uses WebKit.
+func checkEngineMismatch(ua *UserAgent) (string, bool) {
+	if ua.browserFamily != BrowserFirefox && ua.browserFamily != BrowserTor {
+		return "", false
+	}
+
+	if ua.operatingSystem == OSIOS || !appleWebKitRegEx.MatchString(ua.userAgent) {
+		return "", false
+	}
+
+	return ua.browser + " uses Gecko but claims AppleWebKit", true
+}
+
+// isChromiumBased returns true if the browser is always built on Chromium.
+// Edge 18 and older and Opera 12 and older used their own engines.
+func isChromiumBased(ua *UserAgent) bool {
+	if ua.browserFamily == BrowserEdge {
+		return ua.browserVersion.Major() >= 79
+	}
+
+	if ua.browserFamily == BrowserOpera {
+		return ua.browserVersion.Major() >= 15
+	}
+
+	return slices.Contains(chromiumBrowsers[:], ua.browserFamily)
+}
+
+// majorVersion returns the major component of a dotted version, or -1 if the
+// version is empty or not numeric.
+func majorVersion(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+var (
+	windowsPhoneRegEx    = regexp.MustCompile(`(?i)windows phone`)
+	iosPlatformRegEx     = regexp.MustCompile(`(?i)iphone|ipad|ipod`)
+	windowsPlatformRegEx = regexp.MustCompile(`(?i)windows nt|win64|wow64|win32`)
+	androidPlatformRegEx = regexp.MustCompile(`(?i)android`)
+	macPlatformRegEx     = regexp.MustCompile(`(?i)macintosh`)
+	appleWebKitRegEx     = regexp.MustCompile(`(?i)applewebkit`)
+
+	chromiumTokens   = [...]string{"AppleWebKit/", "Chrome/", "Safari/"}
+	chromiumBrowsers = [...]BrowserFamily{
+		BrowserChrome, BrowserSamsungInternet, BrowserVivaldi, BrowserBrave, BrowserYandex, BrowserArc, BrowserOperaGX,
+	}
+
+	lastReleases = [...]lastRelease{
+		{device: "Windows 2000", browser: BrowserChrome, major: 49},
+		{device: "Windows XP", browser: BrowserChrome, major: 49},
+		{device: "Windows Server 2003", browser: BrowserChrome, major: 49},
+		{device: "Windows Vista", browser: BrowserChrome, major: 49},
+		{device: "Windows 7", browser: BrowserChrome, major: 109},
+		{device: "Windows 8", browser: BrowserChrome, major: 109},
+		{device: "Windows 7", browser: BrowserEdge, major: 109},
+		{device: "Windows 8", browser: BrowserEdge, major: 109},
+		{device: "Windows 2000", browser: BrowserFirefox, major: 12},
+		{device: "Windows XP", browser: BrowserFirefox, major: 52},
+		{device: "Windows Vista", browser: BrowserFirefox, major: 52},
+		{device: "Windows 7", browser: BrowserFirefox, major: 115},
+		{device: "Windows 8", browser: BrowserFirefox, major: 115},
+		{device: "Windows XP", browser: BrowserOpera, major: 36},
+		{device: "Windows Vista", browser: BrowserOpera, major: 36},
+		{device: "Windows 7", browser: BrowserOpera, major: 95},
+		{device: "Windows 8", browser: BrowserOpera, major: 95},
+	}
+
+	anomalyRules = [...]anomalyRule{
+		{kind: AnomalyObsoleteOS, check: checkObsoleteOS},
+		{kind: AnomalyUnsupportedOS, check: checkUnsupportedOS},
+		{kind: AnomalyPlatformMismatch, check: checkPlatformMismatch},
+		{kind: AnomalyMissingToken, check: checkMissingToken},
+		{kind: AnomalyVersionMismatch, check: checkVersionMismatch},
+		{kind: AnomalyEngineMismatch, check: checkEngineMismatch},
+	}
+)
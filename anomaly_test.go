@@ -0,0 +1,125 @@
+package useragent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnomalies(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		anomalies []AnomalyKind
+	}{
+		// Real browsers
+		{
+			name:      "Chrome on Windows 10",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:      "Edge on Windows 10",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+		},
+		{
+			name:      "Firefox on Windows 10",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		},
+		{
+			name:      "Safari on macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+		},
+		{
+			name:      "Edge on iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 EdgiOS/120.0.2210.126 Mobile/15E148 Safari/605.1.15",
+		},
+		{
+			name:      "Firefox on iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Mobile/15E148 Safari/605.1.15",
+		},
+		{
+			name:      "Chrome 109 on Windows 7",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36",
+		},
+		{
+			name:      "Windows Phone claiming Android and iPhone",
+			userAgent: "Mozilla/5.0 (Mobile; Windows Phone 8.1; Android 4.0; ARM; Trident/7.0; Touch; rv:11.0; IEMobile/11.0; NOKIA; Lumia 635) like iPhone OS 7_0_3 Mac OS X AppleWebKit/537 (KHTML, like Gecko) Mobile Safari/537",
+		},
+		{
+			name:      "Safari 5 on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/534.57.2 (KHTML, like Gecko) Version/5.1.7 Safari/534.57.2",
+		},
+		// Spoofed user agents
+		{
+			name:      "Chrome 120 on Windows XP",
+			userAgent: "Mozilla/5.0 (Windows NT 5.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			anomalies: []AnomalyKind{AnomalyObsoleteOS},
+		},
+		{
+			name:      "Firefox 121 on Windows 7",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			anomalies: []AnomalyKind{AnomalyObsoleteOS},
+		},
+		{
+			name:      "Safari on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			anomalies: []AnomalyKind{AnomalyUnsupportedOS},
+		},
+		{
+			name:      "Safari on Windows without a version",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/605.1.15 (KHTML, like Gecko) Safari/605.1.15",
+			anomalies: []AnomalyKind{AnomalyUnsupportedOS},
+		},
+		{
+			name:      "iPhone with Win64",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X; Win64) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			anomalies: []AnomalyKind{AnomalyPlatformMismatch},
+		},
+		{
+			name:      "Edge without Chromium tokens",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Edg/120.0.0.0",
+			anomalies: []AnomalyKind{AnomalyMissingToken},
+		},
+		{
+			name:      "Edge with mismatched Chrome version",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			anomalies: []AnomalyKind{AnomalyVersionMismatch},
+		},
+		{
+			name:      "Firefox claiming AppleWebKit on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Firefox/121.0",
+			anomalies: []AnomalyKind{AnomalyEngineMismatch},
+		},
+		{
+			name:      "Chrome 120 on Windows XP without Chromium tokens",
+			userAgent: "Mozilla/5.0 (Windows NT 5.1) Chrome/120.0.0.0",
+			anomalies: []AnomalyKind{AnomalyObsoleteOS, AnomalyMissingToken},
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+
+			var kinds []AnomalyKind
+			for _, a := range ua.Anomalies() {
+				kinds = append(kinds, a.Kind)
+
+				if a.Reason == "" {
+					t.Errorf("expected a reason for anomaly %q", a.Kind)
+				}
+			}
+
+			if !slices.Equal(kinds, tc.anomalies) {
+				t.Errorf("expected anomalies %v, but got %v", tc.anomalies, ua.Anomalies())
+			}
+
+			if ua.IsAnomalous() != (len(tc.anomalies) > 0) {
+				t.Errorf("expected IsAnomalous() %v, but got %v", len(tc.anomalies) > 0, ua.IsAnomalous())
+			}
+		})
+	}
+}
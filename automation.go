@@ -0,0 +1,55 @@
+package useragent
+
+import (
+	"regexp"
+)
+
+// automationPattern holds a pre-compiled regex for matching a headless browser
+// or an automation tool.
+type automationPattern struct {
+	name     string
+	regex    *regexp.Regexp
+	headless bool
+}
+
+// IsHeadless returns true if the user agent is a headless browser such as
+// HeadlessChrome or PhantomJS.
+func (ua *UserAgent) IsHeadless() bool {
+	return ua.headless
+}
+
+// Automation returns the name of the headless browser, browser automation
+// framework or scraping framework that sent the user agent, or an empty string
+// if none was detected. Named crawlers such as Googlebot are never reported
+// as automation; use BotCategory for those.
+func (ua *UserAgent) Automation() string {
+	return ua.automation
+}
+
+func compileAutomation(name, pattern string, headless bool) automationPattern {
+	return automationPattern{
+		name:     name,
+		regex:    regexp.MustCompile(`(?i)` + pattern),
+		headless: headless,
+	}
+}
+
+var automationTools = [...]automationPattern{
+	// Headless browsers
+	compileAutomation("HeadlessChrome", `headlesschrome`, true),
+	compileAutomation("PhantomJS", `phantomjs`, true),
+	compileAutomation("SlimerJS", `slimerjs`, true),
+	// Browser automation frameworks
+	compileAutomation("Puppeteer", `puppeteer`, false),
+	compileAutomation("Playwright", `playwright`, false),
+	compileAutomation("Selenium", `(selenium)|(webdriver)`, false),
+	compileAutomation("Cypress", `cypress/`, false),
+	compileAutomation("Nightmare", `nightmare`, false),
+	// Electron apps name themselves before the Chrome token, scripts run
+	// with the bare electron binary keep the default name "Electron"
+	compileAutomation("Electron", `like gecko\) electron/`, false),
+	// Scraping frameworks. General purpose HTTP libraries such as curl or
+	// OkHttp are also used by apps and services, so they are not automation
+	compileAutomation("Scrapy", `scrapy`, false),
+	compileAutomation("Colly", `colly`, false),
+}
@@ -0,0 +1,121 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestAutomation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		userAgent  string
+		browser    string
+		automation string
+		headless   bool
+		category   BotCategory
+	}{
+		{
+			name:       "HeadlessChrome",
+			userAgent:  "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.6099.28 Safari/537.36",
+			browser:    "Chrome",
+			automation: "HeadlessChrome",
+			headless:   true,
+			category:   BotAutomation,
+		},
+		{
+			name:       "PhantomJS",
+			userAgent:  "Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/538.1 (KHTML, like Gecko) PhantomJS/2.1.1 Safari/538.1",
+			browser:    "Safari",
+			automation: "PhantomJS",
+			headless:   true,
+			category:   BotAutomation,
+		},
+		{
+			name:       "SlimerJS",
+			userAgent:  "Mozilla/5.0 (X11; Linux x86_64; rv:59.0) Gecko/20100101 SlimerJS/1.0.0",
+			browser:    "unknown",
+			automation: "SlimerJS",
+			headless:   true,
+			category:   BotAutomation,
+		},
+		{
+			name:       "Cypress",
+			userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Cypress/13.6.0 Chrome/118.0.5993.159 Electron/27.1.3 Safari/537.36",
+			browser:    "Chrome",
+			automation: "Cypress",
+			category:   BotAutomation,
+		},
+		{
+			name:       "Electron script",
+			userAgent:  "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Electron/28.0.0 Chrome/120.0.6099.56 Electron/28.0.0 Safari/537.36",
+			browser:    "Chrome",
+			automation: "Electron",
+			category:   BotAutomation,
+		},
+		{
+			name:       "Scrapy",
+			userAgent:  "Scrapy/2.11.0 (+https://scrapy.org)",
+			browser:    "[Bot] Other",
+			automation: "Scrapy",
+			category:   BotAutomation,
+		},
+		{
+			name:      "HTTP library is not automation",
+			userAgent: "okhttp/4.9.0",
+			browser:   "[Bot] Other",
+			category:  BotOther,
+		},
+		{
+			name:      "Chrome is not automation",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			browser:   "Chrome",
+			category:  BotNone,
+		},
+		{
+			name:      "Electron app is not automation",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.36.140 Chrome/120.0.6099.56 Electron/28.0.0 Safari/537.36",
+			browser:   "Chrome",
+			category:  BotNone,
+		},
+		{
+			name:      "Named crawler using an HTTP library",
+			userAgent: "LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)",
+			browser:   "[Bot] LinkedInBot",
+			category:  BotSocial,
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			browser:   "[Bot] Googlebot",
+			category:  BotSearchEngine,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.Browser() != tc.browser {
+				t.Errorf("expected browser %q, but got %q", tc.browser, ua.Browser())
+			}
+
+			if ua.Automation() != tc.automation {
+				t.Errorf("expected automation %q, but got %q", tc.automation, ua.Automation())
+			}
+
+			if ua.IsHeadless() != tc.headless {
+				t.Errorf("expected IsHeadless() %v, but got %v", tc.headless, ua.IsHeadless())
+			}
+
+			if ua.BotCategory() != tc.category {
+				t.Errorf("expected bot category %q, but got %q", tc.category, ua.BotCategory())
+			}
+
+			if tc.automation != "" && ua.IsValid() {
+				t.Error("expected IsValid() to be false for automation")
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package useragent
+
+import (
+	"slices"
+)
+
+// Bot describes a bot the parser can detect.
+type Bot struct {
+	// Name is the name reported by Browser, e.g. "[Bot] Googlebot".
+	Name     string
+	Category BotCategory
+	// Tokens are the robots.txt product tokens the bot obeys, most specific
+	// first, followed by the tokens of its crawler variants, such as
+	// AdsBot-Google. Bots that do not read robots.txt have none.
+	Tokens []string
+}
+
+// KnownBots returns every bot the parser can detect, in detection order.
+// Bots matched by more than one rule are listed once.
+func KnownBots() []Bot {
+	var bots []Bot
+
+	for i := range browsers {
+		bp := &browsers[i]
+		if bp.category == BotNone {
+			continue
+		}
+
+		if j := slices.IndexFunc(bots, func(b Bot) bool { return b.Name == bp.name }); j >= 0 {
+			for _, token := range bp.tokens {
+				if !slices.Contains(bots[j].Tokens, token) {
+					bots[j].Tokens = append(bots[j].Tokens, token)
+				}
+			}
+
+			continue
+		}
+
+		bots = append(bots, Bot{
+			Name:     bp.name,
+			Category: bp.category,
+			Tokens:   slices.Clone(bp.tokens),
+		})
+	}
+
+	// Crawler variants belong to the search engine bot whose rule matches
+	// their token
+	for i := range crawlerVariants {
+		cp := &crawlerVariants[i]
+
+		ua := Parse(cp.token)
+		if ua.botCategory != BotSearchEngine {
+			continue
+		}
+
+		j := slices.IndexFunc(bots, func(b Bot) bool { return b.Name == ua.browser })
+		if j >= 0 && !slices.Contains(bots[j].Tokens, cp.token) {
+			bots[j].Tokens = append(bots[j].Tokens, cp.token)
+		}
+	}
+
+	return bots
+}
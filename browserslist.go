@@ -0,0 +1,492 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidBrowserslist is returned for browserslist queries that cannot be
+// parsed or are not supported.
+var ErrInvalidBrowserslist = errors.New("useragent: invalid browserslist query")
+
+// Browserslist is a compiled browserslist query, such as
+// "last 2 Chrome versions, Safari >= 15, not dead". It decides whether the
+// browser of a parsed user agent is supported, so a server can share the
+// configuration the frontend build uses.
+//
+// Supported queries are "defaults", "last N versions", "last N major
+// versions", "last N <browser> versions", "last N <browser> major versions",
+// "<browser> >= X" (and >, <, <=), "<browser> X", "<browser> X-Y",
+// "<browser> all", "Firefox ESR", "dead" and "unreleased versions",
+// combined with ",", "or", "and" and "not". Queries based on usage
+// statistics or release dates, such as "> 0.5%" or "last 2 years", are not
+// supported; "defaults" therefore means "last 2 versions, Firefox ESR, not
+// dead". Node and Electron queries match no browser.
+//
+// Version data is a snapshot bundled with the package. Versions newer than
+// the snapshot are treated as the latest version, so "last 2 versions" keeps
+// matching browsers released after it.
+type Browserslist struct {
+	queries []browserslistQuery
+}
+
+// browserslistCombinator joins a query to the queries before it.
+type browserslistCombinator int
+
+const (
+	browserslistOr browserslistCombinator = iota
+	browserslistAnd
+)
+
+// browserslistQuery is a single query with the way it is combined with the
+// result of the queries before it.
+type browserslistQuery struct {
+	combinator browserslistCombinator
+	not        bool
+	match      func(browserslistTarget) bool
+}
+
+// browserslistTarget is a browser as browserslist names it, e.g. "ios_saf"
+// for every browser on iOS.
+type browserslistTarget struct {
+	name    string
+	version string
+	android bool
+}
+
+// browserslistBrowser is the release data of a browser.
+type browserslistBrowser struct {
+	// versions are the released versions, oldest first. Versions may be a
+	// range such as "18.5-18.6" or "all".
+	versions []string
+}
+
+// ParseBrowserslist compiles a browserslist query.
+func ParseBrowserslist(query string) (*Browserslist, error) {
+	b := &Browserslist{}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	combinator := browserslistOr
+	start := 0
+
+	for _, loc := range append(browserslistSplitRegEx.FindAllStringIndex(query, -1), []int{len(query), len(query)}) {
+		q, err := parseBrowserslistQuery(strings.TrimSpace(query[start:loc[0]]))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b.queries) == 0 && q.not {
+			return nil, fmt.Errorf("%w: %q cannot start with not", ErrInvalidBrowserslist, query)
+		}
+
+		q.combinator = combinator
+		b.queries = append(b.queries, q)
+
+		combinator = browserslistOr
+		if strings.TrimSpace(query[loc[0]:loc[1]]) == "and" {
+			combinator = browserslistAnd
+		}
+
+		start = loc[1]
+	}
+
+	return b, nil
+}
+
+// ParseBrowserslistConfig compiles the queries of a .browserslistrc file for
+// an environment such as "production". Queries outside of a section, or in a
+// [defaults] section, are used when the file has no section for env; a file
+// without queries means "defaults". An empty env means "production".
+func ParseBrowserslistConfig(data []byte, env string) (*Browserslist, error) {
+	if env == "" {
+		env = "production"
+	}
+
+	sections := map[string][]string{}
+	current := []string{"defaults"}
+
+	for line := range strings.Lines(string(data)) {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.Fields(line[1 : len(line)-1])
+			for _, name := range current {
+				if _, ok := sections[name]; ok {
+					return nil, fmt.Errorf("%w: duplicate section %q", ErrInvalidBrowserslist, name)
+				}
+
+				sections[name] = nil
+			}
+
+			continue
+		}
+
+		for _, name := range current {
+			sections[name] = append(sections[name], line)
+		}
+	}
+
+	queries, ok := sections[env]
+	if !ok || len(queries) == 0 {
+		queries = sections["defaults"]
+	}
+
+	if len(queries) == 0 {
+		queries = []string{"defaults"}
+	}
+
+	return ParseBrowserslist(strings.Join(queries, ", "))
+}
+
+// Supports returns true if the browser of the user agent is selected by the
+// query. Browsers browserslist does not know, such as bots, are not
+// supported; browsers built on Chrome or Firefox are checked as the engine
+// version they report.
+func (b *Browserslist) Supports(ua *UserAgent) bool {
+	target, ok := ua.browserslistTarget()
+	if !ok {
+		return false
+	}
+
+	supported := false
+
+	for _, q := range b.queries {
+		matched := q.match(target)
+
+		switch {
+		case q.not:
+			supported = supported && !matched
+		case q.combinator == browserslistAnd:
+			supported = supported && matched
+		default:
+			supported = supported || matched
+		}
+	}
+
+	return supported
+}
+
+// browserslistTarget returns the browserslist name and version of the
+// browser.
+func (ua *UserAgent) browserslistTarget() (browserslistTarget, bool) {
+	android := ua.operatingSystem == OSAndroid
+
+	// Every browser on iOS uses the WebKit of the operating system
+	if ua.operatingSystem == OSIOS && ua.browserFamily != BrowserOperaMini && ua.browserFamily != BrowserBot {
+		return browserslistTarget{name: "ios_saf", version: ua.osVersion.String()}, !ua.osVersion.IsZero()
+	}
+
+	if name, ok := browserslistNames[ua.browserFamily]; ok {
+		return browserslistTarget{name: name, version: ua.browserVersion.String(), android: android}, !ua.browserVersion.IsZero() || name == "op_mini"
+	}
+
+	if ua.browserFamily == BrowserBot || ua.browserFamily == BrowserUnknown {
+		return browserslistTarget{}, false
+	}
+
+	if version := firstSubmatch(browserVersionRegEx[BrowserChrome], ua.userAgent); version != "" {
+		return browserslistTarget{name: "chrome", version: version, android: android}, true
+	}
+
+	if version := firstSubmatch(browserVersionRegEx[BrowserFirefox], ua.userAgent); version != "" {
+		return browserslistTarget{name: "firefox", version: version, android: android}, true
+	}
+
+	return browserslistTarget{}, false
+}
+
+// is returns true if the target is the browser with the browserslist name.
+// Android browsers such as "and_chr" are their desktop counterpart on
+// Android.
+func (t browserslistTarget) is(name string) bool {
+	if desktop, ok := browserslistAndroid[name]; ok {
+		return t.android && t.name == desktop
+	}
+
+	return t.name == name
+}
+
+// parseBrowserslistQuery compiles a single lower case query.
+func parseBrowserslistQuery(query string) (browserslistQuery, error) {
+	if rest, ok := strings.CutPrefix(query, "not "); ok {
+		q, err := parseBrowserslistQuery(strings.TrimSpace(rest))
+		q.not = true
+
+		return q, err
+	}
+
+	match, err := compileBrowserslistQuery(query)
+	if err != nil {
+		return browserslistQuery{}, err
+	}
+
+	return browserslistQuery{match: match}, nil
+}
+
+// compileBrowserslistQuery returns the matcher of a single query without
+// "not".
+func compileBrowserslistQuery(query string) (func(browserslistTarget) bool, error) {
+	switch query {
+	case "defaults":
+		return func(t browserslistTarget) bool {
+			return (browserslistLast(t, 2, false) || browserslistFirefoxESR(t)) && !browserslistDead(t)
+		}, nil
+	case "dead":
+		return browserslistDead, nil
+	case "firefox esr", "ff esr", "fx esr":
+		return browserslistFirefoxESR, nil
+	case "unreleased versions":
+		return func(browserslistTarget) bool { return false }, nil
+	}
+
+	if m := browserslistLastRegEx.FindStringSubmatch(query); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBrowserslist, query)
+		}
+
+		major := m[3] != ""
+		if m[2] == "" {
+			return func(t browserslistTarget) bool { return browserslistLast(t, n, major) }, nil
+		}
+
+		name, ok := browserslistBrowserName(m[2])
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown browser %q", ErrInvalidBrowserslist, m[2])
+		}
+
+		return func(t browserslistTarget) bool { return t.is(name) && browserslistLast(t, n, major) }, nil
+	}
+
+	if m := browserslistUnreleasedRegEx.FindStringSubmatch(query); m != nil {
+		if _, ok := browserslistBrowserName(m[1]); !ok {
+			return nil, fmt.Errorf("%w: unknown browser %q", ErrInvalidBrowserslist, m[1])
+		}
+
+		return func(browserslistTarget) bool { return false }, nil
+	}
+
+	if browserslistNodeRegEx.MatchString(query) {
+		return func(browserslistTarget) bool { return false }, nil
+	}
+
+	if m := browserslistVersionRegEx.FindStringSubmatch(query); m != nil {
+		name, ok := browserslistBrowserName(m[1])
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown browser %q", ErrInvalidBrowserslist, m[1])
+		}
+
+		return browserslistVersionMatcher(name, m[2], m[3], m[4]), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported query %q", ErrInvalidBrowserslist, query)
+}
+
+// browserslistVersionMatcher returns the matcher of "<browser> <op> <version>",
+// "<browser> <from>-<to>" and "<browser> <version>" queries.
+func browserslistVersionMatcher(name, op, version, to string) func(browserslistTarget) bool {
+	return func(t browserslistTarget) bool {
+		if !t.is(name) {
+			return false
+		}
+
+		if version == "all" || t.version == "" {
+			return version == "all"
+		}
+
+		cmp := compareVersions(t.version, version)
+
+		switch op {
+		case ">=":
+			return cmp >= 0
+		case ">":
+			return cmp > 0 && !versionHasPrefix(t.version, version)
+		case "<=":
+			return cmp <= 0 || versionHasPrefix(t.version, version)
+		case "<":
+			return cmp < 0
+		}
+
+		if to != "" {
+			return cmp >= 0 && (compareVersions(t.version, to) <= 0 || versionHasPrefix(t.version, to))
+		}
+
+		return versionHasPrefix(t.version, version)
+	}
+}
+
+// browserslistLast returns true if the target is one of the last n versions,
+// or the last n major versions, of its browser.
+func browserslistLast(t browserslistTarget, n int, major bool) bool {
+	browser, ok := browserslistData[t.name]
+	if !ok {
+		return false
+	}
+
+	versions := browser.versions
+	if major {
+		versions = browserslistMajors(versions)
+	}
+
+	oldest := versions[max(len(versions)-n, 0)]
+	if oldest == "all" {
+		return true
+	}
+
+	oldest, _, _ = strings.Cut(oldest, "-")
+
+	if major {
+		return majorVersion(t.version) >= majorVersion(oldest)
+	}
+
+	return compareVersions(t.version, oldest) >= 0
+}
+
+// browserslistMajors returns the first version of every major version.
+func browserslistMajors(versions []string) []string {
+	var majors []string
+
+	for _, v := range versions {
+		if len(majors) == 0 || v == "all" || majorVersion(v) != majorVersion(majors[len(majors)-1]) {
+			majors = append(majors, v)
+		}
+	}
+
+	return majors
+}
+
+// browserslistDead returns true for browsers without updates for 24 months.
+func browserslistDead(t browserslistTarget) bool {
+	switch t.name {
+	case "ie":
+		return true
+	case "op_mob":
+		return compareVersions(t.version, "12.1") <= 0
+	case "samsung":
+		return majorVersion(t.version) == 4
+	}
+
+	return false
+}
+
+// browserslistFirefoxESR returns true for the extended support releases of
+// Firefox.
+func browserslistFirefoxESR(t browserslistTarget) bool {
+	return t.name == "firefox" && slices.Contains(firefoxESRVersions[:], majorVersion(t.version))
+}
+
+// browserslistBrowserName returns the browserslist name of a browser name or
+// alias. Browsers browserslist knows but the parser does not detect, such as
+// "kaios", are valid and never match.
+func browserslistBrowserName(name string) (string, bool) {
+	if alias, ok := browserslistAliases[name]; ok {
+		name = alias
+	}
+
+	if _, ok := browserslistData[name]; ok {
+		return name, true
+	}
+
+	if _, ok := browserslistAndroid[name]; ok {
+		return name, true
+	}
+
+	return name, slices.Contains(browserslistUndetected[:], name)
+}
+
+// majorRange returns the versions from to to as strings.
+func majorRange(from, to int) []string {
+	versions := make([]string, 0, to-from+1)
+	for v := from; v <= to; v++ {
+		versions = append(versions, strconv.Itoa(v))
+	}
+
+	return versions
+}
+
+var (
+	browserslistSplitRegEx      = regexp.MustCompile(`(?i)\s*,\s*|\s+or\s+|\s+and\s+`)
+	browserslistLastRegEx       = regexp.MustCompile(`^last\s+(\d+)\s+(?:([a-z_]+)\s+)?(major\s+)?versions?$`)
+	browserslistUnreleasedRegEx = regexp.MustCompile(`^unreleased\s+([a-z_]+)\s+versions?$`)
+	browserslistVersionRegEx    = regexp.MustCompile(`^([a-z_]+)\s*(>=|<=|>|<)?\s*(all|[\d.]+)(?:\s*-\s*([\d.]+))?$`)
+	browserslistNodeRegEx       = regexp.MustCompile(`^(?:maintained node versions|current node|(?:node|electron)\b.*)$`)
+
+	// browserslistNames are the browserslist names of browser families.
+	// Families missing here are checked by their Chrome or Firefox version.
+	browserslistNames = map[BrowserFamily]string{
+		BrowserChrome:           "chrome",
+		BrowserEdge:             "edge",
+		BrowserFirefox:          "firefox",
+		BrowserSafari:           "safari",
+		BrowserOpera:            "opera",
+		BrowserOperaMobile:      "op_mob",
+		BrowserOperaMini:        "op_mini",
+		BrowserSamsungInternet:  "samsung",
+		BrowserInternetExplorer: "ie",
+		BrowserUCBrowser:        "and_uc",
+	}
+
+	// browserslistAliases map alternative names to the names used in
+	// browserslistData and browserslistAndroid.
+	browserslistAliases = map[string]string{
+		"explorer":        "ie",
+		"ff":              "firefox",
+		"fx":              "firefox",
+		"firefoxandroid":  "and_ff",
+		"chromeandroid":   "and_chr",
+		"android":         "and_chr",
+		"ios":             "ios_saf",
+		"operamobile":     "op_mob",
+		"operamini":       "op_mini",
+		"ucandroid":       "and_uc",
+		"samsunginternet": "samsung",
+	}
+
+	// browserslistAndroid maps Android browsers to their desktop counterparts,
+	// whose data they share.
+	browserslistAndroid = map[string]string{
+		"and_chr": "chrome",
+		"and_ff":  "firefox",
+	}
+
+	// browserslistUndetected are browsers browserslist knows that the parser
+	// does not detect.
+	browserslistUndetected = [...]string{"and_qq", "baidu", "bb", "blackberry", "ie_mob", "kaios", "qqandroid"}
+
+	firefoxESRVersions = [...]int{115, 140}
+
+	// browserslistData is a snapshot of the browserslist release data.
+	browserslistData = map[string]browserslistBrowser{
+		"chrome":  {versions: majorRange(100, 141)},
+		"edge":    {versions: majorRange(100, 141)},
+		"firefox": {versions: majorRange(100, 144)},
+		"opera":   {versions: majorRange(86, 122)},
+		"safari": {versions: []string{
+			"15", "15.1", "15.2-15.3", "15.4", "15.5", "15.6",
+			"16.0", "16.1", "16.2", "16.3", "16.4", "16.5", "16.6",
+			"17.0", "17.1", "17.2", "17.3", "17.4", "17.5", "17.6",
+			"18.0", "18.1", "18.2", "18.3", "18.4", "18.5-18.6", "26.0",
+		}},
+		"ios_saf": {versions: []string{
+			"15.0-15.1", "15.2-15.3", "15.4", "15.5", "15.6-15.8",
+			"16.0", "16.1", "16.2", "16.3", "16.4", "16.5", "16.6-16.7",
+			"17.0", "17.1", "17.2", "17.3", "17.4", "17.5", "17.6-17.7",
+			"18.0", "18.1", "18.2", "18.3", "18.4", "18.5-18.6", "26.0",
+		}},
+		"samsung": {versions: []string{"4", "5.0-5.4", "6.2-6.4", "7.2-7.4", "8.2", "9.2", "10.1", "11.1-11.2", "12.0",
+			"13.0", "14.0", "15.0", "16.0", "17.0", "18.0", "19.0", "20", "21", "22", "23", "24", "25", "26", "27", "28"}},
+		"op_mob":  {versions: []string{"10", "11", "11.1", "11.5", "12", "12.1", "80"}},
+		"op_mini": {versions: []string{"all"}},
+		"ie":      {versions: []string{"5.5", "6", "7", "8", "9", "10", "11"}},
+		"and_uc":  {versions: []string{"15.5"}},
+	}
+)
@@ -0,0 +1,152 @@
+package useragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBrowserslistSupports(t *testing.T) {
+	const (
+		chrome141    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36"
+		chrome109    = "Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36"
+		chrome150    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/150.0.0.0 Safari/537.36"
+		firefox115   = "Mozilla/5.0 (Windows NT 6.1; Win64; x64; rv:115.0) Gecko/20100101 Firefox/115.0"
+		firefox130   = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:130.0) Gecko/20100101 Firefox/130.0"
+		safari145    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.2 Safari/605.1.15"
+		safari176    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Safari/605.1.15"
+		iosChrome    = "Mozilla/5.0 (iPhone; CPU iPhone OS 18_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/141.0.7390.96 Mobile/15E148 Safari/604.1"
+		ie11         = "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko"
+		brave        = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36 Brave/141"
+		vivaldi      = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36 Vivaldi/7.6.3797.52"
+		androidChr   = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Mobile Safari/537.36"
+		androidFx    = "Mozilla/5.0 (Android 14; Mobile; rv:144.0) Gecko/144.0 Firefox/144.0"
+		androidEdge  = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Mobile Safari/537.36 EdgA/141.0.3537.85"
+		operaMini    = "Opera/9.80 (J2ME/MIDP; Opera Mini/5.1.21214/28.2725; U; en) Presto/2.8.119 Version/11.10"
+		googlebot    = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+		emptyVersion = ""
+	)
+
+	testCases := []struct {
+		name      string
+		query     string
+		userAgent string
+		supported bool
+	}{
+		{name: "last 2 Chrome versions latest", query: "last 2 Chrome versions", userAgent: chrome141, supported: true},
+		{name: "last 2 Chrome versions old", query: "last 2 Chrome versions", userAgent: chrome109, supported: false},
+		{name: "last 2 Chrome versions newer than data", query: "last 2 Chrome versions", userAgent: chrome150, supported: true},
+		{name: "last 2 Chrome versions other browser", query: "last 2 Chrome versions", userAgent: firefox130, supported: false},
+		{name: "Chrome at least", query: "Chrome >= 109", userAgent: chrome109, supported: true},
+		{name: "Chrome greater", query: "chrome > 109", userAgent: chrome109, supported: false},
+		{name: "Chrome less or equal", query: "chrome <= 109", userAgent: chrome109, supported: true},
+		{name: "Chrome range", query: "chrome 100-110", userAgent: chrome109, supported: true},
+		{name: "Chrome exact", query: "chrome 109", userAgent: chrome109, supported: true},
+		{name: "Safari at least", query: "Safari >= 15", userAgent: safari145, supported: false},
+		{name: "Safari at least newer", query: "Safari >= 15", userAgent: safari176, supported: true},
+		{name: "Safari last 2 major versions", query: "last 2 Safari major versions", userAgent: safari176, supported: false},
+		{name: "iOS browsers use iOS Safari", query: "iOS >= 18", userAgent: iosChrome, supported: true},
+		{name: "iOS browsers are not Chrome", query: "Chrome >= 100", userAgent: iosChrome, supported: false},
+		{name: "Last 2 versions", query: "last 2 versions", userAgent: firefox130, supported: false},
+		{name: "Firefox ESR", query: "Firefox ESR", userAgent: firefox115, supported: true},
+		{name: "Defaults ESR", query: "defaults", userAgent: firefox115, supported: true},
+		{name: "Defaults latest", query: "defaults", userAgent: chrome141, supported: true},
+		{name: "Defaults old", query: "defaults", userAgent: chrome109, supported: false},
+		{name: "Not dead", query: "last 2 versions, not dead", userAgent: ie11, supported: false},
+		{name: "IE without not dead", query: "last 2 versions", userAgent: ie11, supported: true},
+		{name: "And", query: "chrome >= 100 and chrome < 120", userAgent: chrome141, supported: false},
+		{name: "Or", query: "chrome >= 140 or firefox >= 130", userAgent: firefox130, supported: true},
+		{name: "And not", query: "chrome >= 100 and not chrome 109", userAgent: chrome109, supported: false},
+		{name: "Chromium based browser", query: "chrome >= 140", userAgent: brave, supported: true},
+		{name: "Chromium based browser own version", query: "chrome >= 141", userAgent: vivaldi, supported: false},
+		{name: "Android browser", query: "last 1 edge version", userAgent: androidEdge, supported: true},
+		{name: "Chrome for Android", query: "and_chr >= 141", userAgent: androidChr, supported: true},
+		{name: "Chrome for Android on desktop", query: "and_chr >= 141", userAgent: chrome141, supported: false},
+		{name: "Android alias on desktop", query: "android >= 100", userAgent: chrome141, supported: false},
+		{name: "Firefox for Android", query: "last 1 and_ff version", userAgent: androidFx, supported: true},
+		{name: "Firefox for Android on desktop", query: "last 1 and_ff version", userAgent: firefox130, supported: false},
+		{name: "Opera Mini all", query: "op_mini all", userAgent: operaMini, supported: true},
+		{name: "Not Opera Mini", query: "defaults, not op_mini all", userAgent: operaMini, supported: false},
+		{name: "Bot", query: "defaults", userAgent: googlebot, supported: false},
+		{name: "Empty user agent", query: "defaults", userAgent: emptyVersion, supported: false},
+		{name: "Node", query: "maintained node versions", userAgent: chrome141, supported: false},
+		{name: "Undetected browser", query: "kaios >= 2.5, chrome 141", userAgent: chrome141, supported: true},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := ParseBrowserslist(tc.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if supported := b.Supports(Parse(tc.userAgent)); supported != tc.supported {
+				t.Errorf("expected %q to support %q to be %t", tc.query, tc.userAgent, tc.supported)
+			}
+		})
+	}
+}
+
+func TestParseBrowserslistErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{"", "> 0.5%", "last 2 years", "not dead", "last 0 versions", "netscape >= 4", "supports es6-module"} {
+		if _, err := ParseBrowserslist(query); !errors.Is(err, ErrInvalidBrowserslist) {
+			t.Errorf("expected ErrInvalidBrowserslist for %q, but got %v", query, err)
+		}
+	}
+}
+
+func TestParseBrowserslistConfig(t *testing.T) {
+	t.Parallel()
+
+	config := []byte(`# Shared with the frontend build
+last 2 Chrome versions
+
+[production staging]
+chrome >= 109 # Windows 7
+not ie 11
+
+[development]
+last 1 chrome version
+`)
+
+	chrome109 := Parse("Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36")
+
+	testCases := []struct {
+		env       string
+		supported bool
+	}{
+		{env: "", supported: true},
+		{env: "staging", supported: true},
+		{env: "development", supported: false},
+		{env: "test", supported: false},
+	}
+
+	for _, tc := range testCases {
+		b, err := ParseBrowserslistConfig(config, tc.env)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tc.env, err)
+		}
+
+		if b.Supports(chrome109) != tc.supported {
+			t.Errorf("expected Chrome 109 support in %q to be %t", tc.env, tc.supported)
+		}
+	}
+
+	if _, err := ParseBrowserslistConfig([]byte("[production]\nchrome 1\n[production]\nchrome 2\n"), ""); !errors.Is(err, ErrInvalidBrowserslist) {
+		t.Errorf("expected ErrInvalidBrowserslist for duplicate sections, but got %v", err)
+	}
+
+	b, err := ParseBrowserslistConfig(nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error for empty config: %v", err)
+	}
+
+	if !b.Supports(Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36")) {
+		t.Error("expected empty config to use defaults")
+	}
+}
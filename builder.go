@@ -0,0 +1,302 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedBrowser is returned by Builder.Build for browsers it
+	// cannot produce a user agent for.
+	ErrUnsupportedBrowser = errors.New("useragent: unsupported browser")
+	// ErrUnsupportedPlatform is returned by Builder.Build for combinations of
+	// browser, operating system and device type that do not exist.
+	ErrUnsupportedPlatform = errors.New("useragent: unsupported platform")
+	// ErrMissingVersion is returned by Builder.Build when a required version
+	// is empty.
+	ErrMissingVersion = errors.New("useragent: missing version")
+	// ErrInvalidVersion is returned by Builder.Build and ParseVersion for
+	// versions that are not made of numbers separated by dots.
+	ErrInvalidVersion = errors.New("useragent: invalid version")
+
+	builderVersionRegEx = regexp.MustCompile(`^\d+(?:\.\d+)*$`)
+)
+
+// Builder produces a user agent string from a structured description, in the
+// exact format the real browser uses. Parsing the result gives back the same
+// browser family, browser version, operating system, operating system
+// version, device model and device type, as far as the real format includes
+// them; Firefox on Android, for example, never sends a device model.
+//
+// Supported browsers are Chrome, Edge, Opera, Firefox and Safari on Windows,
+// macOS, Linux, Chrome OS, Android and iOS, wherever the real browser exists.
+type Builder struct {
+	Browser        BrowserFamily
+	BrowserVersion string
+	OS             OSFamily
+	// OSVersion is the version as reported by OSVersion, e.g. "10.0" for
+	// Windows 10, "14.2" for macOS and "14" for Android.
+	OSVersion string
+	// DeviceModel is the Android model, e.g. "Pixel 8". On iOS it may be
+	// "iPhone", "iPad" or "iPod" and is derived from DeviceType when empty.
+	DeviceModel string
+	// DeviceType defaults to desktop, or mobile on Android and iOS.
+	DeviceType DeviceType
+}
+
+// String returns the user agent string, or an empty string if the
+// description is not supported. Use Build to get the error.
+func (b Builder) String() string {
+	userAgent, err := b.Build()
+	if err != nil {
+		return ""
+	}
+
+	return userAgent
+}
+
+// Build returns the user agent string for the description.
+func (b Builder) Build() (string, error) {
+	if b.BrowserVersion == "" {
+		return "", fmt.Errorf("%w: browser version is required", ErrMissingVersion)
+	}
+
+	if b.OS != OSLinux && b.OSVersion == "" {
+		return "", fmt.Errorf("%w: %s version is required", ErrMissingVersion, b.OS)
+	}
+
+	if !builderVersionRegEx.MatchString(b.BrowserVersion) {
+		return "", fmt.Errorf("%w: browser version %q", ErrInvalidVersion, b.BrowserVersion)
+	}
+
+	if b.OSVersion != "" && !builderVersionRegEx.MatchString(b.OSVersion) {
+		return "", fmt.Errorf("%w: %s version %q", ErrInvalidVersion, b.OS, b.OSVersion)
+	}
+
+	platform, err := b.platform()
+	if err != nil {
+		return "", err
+	}
+
+	switch b.Browser {
+	case BrowserChrome:
+		return b.buildChromium(platform, "")
+	case BrowserEdge:
+		return b.buildChromium(platform, b.edgeToken())
+	case BrowserOpera:
+		return b.buildChromium(platform, "OPR/"+b.BrowserVersion)
+	case BrowserFirefox:
+		return b.buildFirefox(platform)
+	case BrowserSafari:
+		return b.buildSafari(platform)
+	case BrowserDuckDuckGo, BrowserBrave, BrowserSamsungInternet, BrowserUCBrowser, BrowserOperaMini,
+		BrowserOperaMobile, BrowserYandex, Browser360Safe, BrowserVivaldi, BrowserArc, BrowserOperaGX,
+		BrowserTor, BrowserLynx, BrowserSeaMonkey, BrowserPaleMoon, BrowserMidori, BrowserAvast,
+		BrowserInternetExplorer, BrowserBot, BrowserUnknown:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedBrowser, b.Browser)
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedBrowser, b.Browser)
+}
+
+// platform returns the platform comment of the user agent, without the
+// parentheses and without the Firefox "rv:" field.
+func (b Builder) platform() (string, error) {
+	deviceType := b.deviceType()
+	mobileOS := b.OS == OSAndroid || b.OS == OSIOS
+
+	if mobileOS == (deviceType == DeviceTypeDesktop) {
+		return "", fmt.Errorf("%w: %s on %s", ErrUnsupportedPlatform, deviceType, b.OS)
+	}
+
+	switch b.OS {
+	case OSWindows:
+		return "Windows NT " + b.OSVersion + "; Win64; x64", nil
+	case OSMacOS:
+		return "Macintosh; Intel Mac OS X " + strings.ReplaceAll(b.OSVersion, ".", "_"), nil
+	case OSLinux:
+		return "X11; Linux x86_64", nil
+	case OSChromeOS:
+		return "X11; CrOS x86_64 " + b.OSVersion, nil
+	case OSAndroid:
+		return "Linux; Android " + b.OSVersion + "; " + b.androidModel(), nil
+	case OSIOS:
+		iosVersion := strings.ReplaceAll(b.OSVersion, ".", "_")
+		if b.iosModel() == "iPad" {
+			return "iPad; CPU OS " + iosVersion + " like Mac OS X", nil
+		}
+
+		if b.iosModel() == "iPod" {
+			return "iPod touch; CPU iPhone OS " + iosVersion + " like Mac OS X", nil
+		}
+
+		return "iPhone; CPU iPhone OS " + iosVersion + " like Mac OS X", nil
+	case OSUbuntu, OSSuse, OSRedhat, OSFedora, OSCentOS, OSBlackBerry, OSQNX, OSBeOS, OSOS2, OSBot, OSUnknown:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedPlatform, b.OS)
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedPlatform, b.OS)
+}
+
+// buildChromium returns the user agent of Chrome or a browser built on it,
+// with suffix appended to the Chrome user agent.
+func (b Builder) buildChromium(platform, suffix string) (string, error) {
+	if b.OS == OSIOS {
+		return b.buildChromiumIOS(platform, suffix)
+	}
+
+	chromeVersion := b.BrowserVersion
+	if b.Browser != BrowserChrome {
+		chromeVersion = b.chromiumMajor() + ".0.0.0"
+	}
+
+	safari := "Safari/537.36"
+	if b.OS == OSAndroid && b.deviceType() == DeviceTypeMobile {
+		safari = "Mobile " + safari
+	}
+
+	userAgent := "Mozilla/5.0 (" + platform + ") AppleWebKit/537.36 (KHTML, like Gecko) Chrome/" + chromeVersion + " " + safari
+	if suffix != "" {
+		userAgent += " " + suffix
+	}
+
+	return userAgent, nil
+}
+
+// buildChromiumIOS returns the user agent of Chrome or Edge on iOS, where
+// both use WebKit and send no Chrome token.
+func (b Builder) buildChromiumIOS(platform, suffix string) (string, error) {
+	if b.Browser == BrowserChrome {
+		return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/" + b.BrowserVersion +
+			" Mobile/15E148 Safari/604.1", nil
+	}
+
+	if b.Browser == BrowserEdge {
+		return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) Version/" + b.osMajor() + ".0 " +
+			suffix + " Mobile/15E148 Safari/605.1.15", nil
+	}
+
+	return "", fmt.Errorf("%w: %s on %s", ErrUnsupportedPlatform, b.Browser, b.OS)
+}
+
+// buildFirefox returns the user agent of Firefox, which does not exist on
+// Chrome OS.
+func (b Builder) buildFirefox(platform string) (string, error) {
+	if b.OS == OSIOS {
+		return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/" + b.BrowserVersion +
+			" Mobile/15E148 Safari/605.1.15", nil
+	}
+
+	if b.OS == OSAndroid {
+		formFactor := "Mobile"
+		if b.deviceType() == DeviceTypeTablet {
+			formFactor = "Tablet"
+		}
+
+		return "Mozilla/5.0 (Android " + b.OSVersion + "; " + formFactor + "; rv:" + b.BrowserVersion + ") Gecko/" +
+			b.BrowserVersion + " Firefox/" + b.BrowserVersion, nil
+	}
+
+	if b.OS == OSChromeOS {
+		return "", fmt.Errorf("%w: %s on %s", ErrUnsupportedPlatform, b.Browser, b.OS)
+	}
+
+	// Firefox separates the macOS version with dots
+	if b.OS == OSMacOS {
+		platform = "Macintosh; Intel Mac OS X " + b.OSVersion
+	}
+
+	return "Mozilla/5.0 (" + platform + "; rv:" + b.BrowserVersion + ") Gecko/20100101 Firefox/" + b.BrowserVersion, nil
+}
+
+// buildSafari returns the user agent of Safari, which only exists on Apple
+// platforms.
+func (b Builder) buildSafari(platform string) (string, error) {
+	if b.OS == OSMacOS {
+		return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) Version/" + b.BrowserVersion +
+			" Safari/605.1.15", nil
+	}
+
+	if b.OS == OSIOS {
+		return "Mozilla/5.0 (" + platform + ") AppleWebKit/605.1.15 (KHTML, like Gecko) Version/" + b.BrowserVersion +
+			" Mobile/15E148 Safari/604.1", nil
+	}
+
+	return "", fmt.Errorf("%w: %s on %s", ErrUnsupportedPlatform, b.Browser, b.OS)
+}
+
+// edgeToken returns the Edge token for the operating system.
+func (b Builder) edgeToken() string {
+	if b.OS == OSAndroid {
+		return "EdgA/" + b.BrowserVersion
+	}
+
+	if b.OS == OSIOS {
+		return "EdgiOS/" + b.BrowserVersion
+	}
+
+	return "Edg/" + b.BrowserVersion
+}
+
+// chromiumMajor returns the major Chrome version the browser is built on.
+// Edge keeps the Chrome major version and Opera is 14 versions behind.
+func (b Builder) chromiumMajor() string {
+	major := majorVersion(b.BrowserVersion)
+	if b.Browser == BrowserOpera {
+		major += 14
+	}
+
+	return strconv.Itoa(major)
+}
+
+// osMajor returns the major operating system version.
+func (b Builder) osMajor() string {
+	major, _, _ := strings.Cut(b.OSVersion, ".")
+
+	return major
+}
+
+// androidModel returns the Android device model, or "K" when no model is set
+// as Chrome does since the user agent reduction.
+func (b Builder) androidModel() string {
+	if b.DeviceModel != "" {
+		return b.DeviceModel
+	}
+
+	return "K"
+}
+
+// iosModel returns the iOS device model, derived from the device type when
+// no model is set.
+func (b Builder) iosModel() string {
+	if b.DeviceModel != "" {
+		return b.DeviceModel
+	}
+
+	if b.deviceType() == DeviceTypeTablet {
+		return "iPad"
+	}
+
+	return "iPhone"
+}
+
+// deviceType returns the device type, defaulting to mobile on Android and
+// iOS and desktop elsewhere.
+func (b Builder) deviceType() DeviceType {
+	if b.DeviceType != "" {
+		return b.DeviceType
+	}
+
+	if b.OS == OSIOS && b.DeviceModel == "iPad" {
+		return DeviceTypeTablet
+	}
+
+	if b.OS == OSAndroid || b.OS == OSIOS {
+		return DeviceTypeMobile
+	}
+
+	return DeviceTypeDesktop
+}
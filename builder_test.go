@@ -0,0 +1,202 @@
+package useragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		name      string
+		builder   Builder
+		userAgent string
+	}{
+		{
+			name:      "Chrome on Windows 10",
+			builder:   Builder{Browser: BrowserChrome, BrowserVersion: "120.0.0.0", OS: OSWindows, OSVersion: "10.0"},
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:      "Chrome on Android",
+			builder:   Builder{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSAndroid, OSVersion: "14", DeviceModel: "Pixel 8"},
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.135 Mobile Safari/537.36",
+		},
+		{
+			name:      "Edge on Windows 10",
+			builder:   Builder{Browser: BrowserEdge, BrowserVersion: "120.0.2210.91", OS: OSWindows, OSVersion: "10.0"},
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+		},
+		{
+			name:      "Firefox on Linux",
+			builder:   Builder{Browser: BrowserFirefox, BrowserVersion: "121.0", OS: OSLinux},
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		},
+		{
+			name:      "Firefox on macOS",
+			builder:   Builder{Browser: BrowserFirefox, BrowserVersion: "121.0", OS: OSMacOS, OSVersion: "10.15"},
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:121.0) Gecko/20100101 Firefox/121.0",
+		},
+		{
+			name:      "Safari on macOS",
+			builder:   Builder{Browser: BrowserSafari, BrowserVersion: "17.2", OS: OSMacOS, OSVersion: "14.2"},
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+		},
+		{
+			name:      "Safari on iPhone",
+			builder:   Builder{Browser: BrowserSafari, BrowserVersion: "17.2", OS: OSIOS, OSVersion: "17.2"},
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		},
+		{
+			name:      "Safari on iPad",
+			builder:   Builder{Browser: BrowserSafari, BrowserVersion: "17.2", OS: OSIOS, OSVersion: "17.2", DeviceType: DeviceTypeTablet},
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.builder.String(); got != tc.userAgent {
+				t.Errorf("expected user agent %q, but got %q", tc.userAgent, got)
+			}
+		})
+	}
+}
+
+func TestBuilderRoundTrip(t *testing.T) {
+	builders := []Builder{
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSWindows, OSVersion: "10.0"},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSMacOS, OSVersion: "10.15.7"},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSLinux},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSChromeOS, OSVersion: "14541.0.0"},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSAndroid, OSVersion: "14", DeviceModel: "Pixel 8"},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.135", OS: OSAndroid, OSVersion: "13", DeviceModel: "SM-X710", DeviceType: DeviceTypeTablet},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.73", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPhone"},
+		{Browser: BrowserChrome, BrowserVersion: "131.0.6778.73", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPad"},
+		{Browser: BrowserEdge, BrowserVersion: "131.0.2903.86", OS: OSWindows, OSVersion: "10.0"},
+		{Browser: BrowserEdge, BrowserVersion: "131.0.2903.87", OS: OSAndroid, OSVersion: "14", DeviceModel: "SM-S918B"},
+		{Browser: BrowserEdge, BrowserVersion: "131.2903.92", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPhone"},
+		{Browser: BrowserOpera, BrowserVersion: "115.0.0.0", OS: OSWindows, OSVersion: "10.0"},
+		{Browser: BrowserOpera, BrowserVersion: "86.0.4524.82", OS: OSAndroid, OSVersion: "14", DeviceModel: "Pixel 7"},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSWindows, OSVersion: "10.0"},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSMacOS, OSVersion: "10.15"},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSLinux},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSAndroid, OSVersion: "14"},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSAndroid, OSVersion: "14", DeviceType: DeviceTypeTablet},
+		{Browser: BrowserFirefox, BrowserVersion: "133.0", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPhone"},
+		{Browser: BrowserSafari, BrowserVersion: "18.1", OS: OSMacOS, OSVersion: "10.15.7"},
+		{Browser: BrowserSafari, BrowserVersion: "18.1", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPhone"},
+		{Browser: BrowserSafari, BrowserVersion: "18.1", OS: OSIOS, OSVersion: "18.1", DeviceModel: "iPad"},
+		{Browser: BrowserSafari, BrowserVersion: "12.1", OS: OSIOS, OSVersion: "12.5", DeviceModel: "iPod"},
+	}
+
+	t.Parallel()
+
+	for _, b := range builders {
+		t.Run(b.Browser.String()+" on "+b.OS.String()+" "+b.DeviceModel, func(t *testing.T) {
+			t.Parallel()
+
+			userAgent, err := b.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ua := Parse(userAgent)
+			if ua.BrowserFamily() != b.Browser {
+				t.Errorf("expected browser %q, but got %q in %q", b.Browser, ua.BrowserFamily(), userAgent)
+			}
+
+			if ua.BrowserVersion().String() != b.BrowserVersion {
+				t.Errorf("expected browser version %q, but got %q in %q", b.BrowserVersion, ua.BrowserVersion(), userAgent)
+			}
+
+			if ua.OSFamily() != b.OS {
+				t.Errorf("expected OS %q, but got %q in %q", b.OS, ua.OSFamily(), userAgent)
+			}
+
+			if ua.OSVersion().String() != b.OSVersion {
+				t.Errorf("expected OS version %q, but got %q in %q", b.OSVersion, ua.OSVersion(), userAgent)
+			}
+
+			if ua.DeviceModel() != b.DeviceModel {
+				t.Errorf("expected device model %q, but got %q in %q", b.DeviceModel, ua.DeviceModel(), userAgent)
+			}
+
+			if ua.TypedDeviceType() != b.deviceType() {
+				t.Errorf("expected device type %q, but got %q in %q", b.deviceType(), ua.TypedDeviceType(), userAgent)
+			}
+
+			if !ua.IsValid() {
+				t.Errorf("expected IsValid() to be true for %q", userAgent)
+			}
+
+			if ua.IsAnomalous() {
+				t.Errorf("expected no anomalies for %q, but got %v", userAgent, ua.Anomalies())
+			}
+		})
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder Builder
+		err     error
+	}{
+		{
+			name:    "Safari on Windows",
+			builder: Builder{Browser: BrowserSafari, BrowserVersion: "17.2", OS: OSWindows, OSVersion: "10.0"},
+			err:     ErrUnsupportedPlatform,
+		},
+		{
+			name:    "Desktop Android",
+			builder: Builder{Browser: BrowserChrome, BrowserVersion: "120.0.0.0", OS: OSAndroid, OSVersion: "14", DeviceType: DeviceTypeDesktop},
+			err:     ErrUnsupportedPlatform,
+		},
+		{
+			name:    "Unsupported browser",
+			builder: Builder{Browser: BrowserLynx, BrowserVersion: "2.9", OS: OSLinux},
+			err:     ErrUnsupportedBrowser,
+		},
+		{
+			name:    "Missing browser version",
+			builder: Builder{Browser: BrowserChrome, OS: OSLinux},
+			err:     ErrMissingVersion,
+		},
+		{
+			name:    "Missing OS version",
+			builder: Builder{Browser: BrowserChrome, BrowserVersion: "120.0.0.0", OS: OSWindows},
+			err:     ErrMissingVersion,
+		},
+		{
+			name:    "Invalid browser version",
+			builder: Builder{Browser: BrowserChrome, BrowserVersion: "latest", OS: OSLinux},
+			err:     ErrInvalidVersion,
+		},
+		{
+			name:    "Invalid OS version",
+			builder: Builder{Browser: BrowserChrome, BrowserVersion: "120.0.0.0", OS: OSWindows, OSVersion: "ten"},
+			err:     ErrInvalidVersion,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.builder.Build()
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error %v, but got %v", tc.err, err)
+			}
+
+			if tc.builder.String() != "" {
+				t.Errorf("expected String() to be empty, but got %q", tc.builder.String())
+			}
+		})
+	}
+}
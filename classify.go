@@ -0,0 +1,191 @@
+package useragent
+
+import (
+	"strings"
+)
+
+// Class is whether a user agent belongs to a human, a bot or cannot be
+// told.
+type Class string
+
+// Classes reported by Classify.
+const (
+	// ClassHuman is a recognized browser on a recognized operating system.
+	ClassHuman Class = "human"
+	// ClassBot is a user agent with a positive bot signal, such as a known
+	// crawler, an automation tool or an attack payload.
+	ClassBot Class = "bot"
+	// ClassUnknown is a user agent without bot signals that is not a
+	// recognized browser either, such as an unknown app.
+	ClassUnknown Class = "unknown"
+)
+
+// String returns the class as a string.
+func (c Class) String() string {
+	return string(c)
+}
+
+// AllClasses returns every class Classify can report.
+func AllClasses() []Class {
+	return []Class{
+		ClassHuman,
+		ClassBot,
+		ClassUnknown,
+	}
+}
+
+// ReasonKind is a signal that contributed to a classification.
+type ReasonKind string
+
+// Reason kinds reported by Classify.
+const (
+	// ReasonEmpty is an empty user agent, which browsers never send.
+	ReasonEmpty ReasonKind = "empty"
+	// ReasonBotRule is a rule naming a known bot, such as Googlebot.
+	ReasonBotRule ReasonKind = "bot_rule"
+	// ReasonScanner is a security scanner or attack tool, see
+	// UserAgent.Scanner.
+	ReasonScanner ReasonKind = "scanner"
+	// ReasonThreat is an attack payload in the user agent, see
+	// UserAgent.Threats.
+	ReasonThreat ReasonKind = "threat"
+	// ReasonGenericBot is a generic bot signal, such as "bot" or "crawler"
+	// in a product name or a URL in a comment.
+	ReasonGenericBot ReasonKind = "generic_bot"
+	// ReasonAutomation is a headless browser, automation framework or
+	// scraping framework.
+	ReasonAutomation ReasonKind = "automation"
+	// ReasonBrowser is a recognized browser on a recognized operating
+	// system.
+	ReasonBrowser ReasonKind = "browser"
+	// ReasonGenericClient is a client that no rule recognized, see
+	// UserAgent.IsGeneric.
+	ReasonGenericClient ReasonKind = "generic_client"
+	// ReasonMissingBrowser is a user agent without a recognized browser.
+	ReasonMissingBrowser ReasonKind = "missing_browser"
+	// ReasonMissingOS is a user agent without a recognized operating system.
+	ReasonMissingOS ReasonKind = "missing_os"
+	// ReasonMissingDevice is a user agent without a recognized device.
+	ReasonMissingDevice ReasonKind = "missing_device"
+	// ReasonAnomaly is an inconsistency that suggests a spoofed user agent,
+	// see UserAgent.Anomalies.
+	ReasonAnomaly ReasonKind = "anomaly"
+)
+
+// String returns the reason kind as a string.
+func (k ReasonKind) String() string {
+	return string(k)
+}
+
+// Reason is a signal that contributed to a classification.
+type Reason struct {
+	Kind   ReasonKind
+	Detail string
+}
+
+// String returns the reason as "kind: detail", or the kind alone if there is
+// no detail.
+func (r Reason) String() string {
+	if r.Detail == "" {
+		return r.Kind.String()
+	}
+
+	return r.Kind.String() + ": " + r.Detail
+}
+
+// Classification is the class of a user agent with the reasons for it.
+type Classification struct {
+	Class   Class
+	Reasons []Reason
+}
+
+// String returns the class followed by its reasons, e.g.
+// "bot (bot_rule: [Bot] Googlebot)".
+func (c Classification) String() string {
+	if len(c.Reasons) == 0 {
+		return c.Class.String()
+	}
+
+	reasons := make([]string, 0, len(c.Reasons))
+	for _, r := range c.Reasons {
+		reasons = append(reasons, r.String())
+	}
+
+	return c.Class.String() + " (" + strings.Join(reasons, ", ") + ")"
+}
+
+// Has returns true if the classification has a reason of the kind.
+func (c Classification) Has(kind ReasonKind) bool {
+	for _, r := range c.Reasons {
+		if r.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Classify tells whether the user agent belongs to a human, a bot or cannot
+// be told, with the reasons for it. Unlike IsBot, which treats every user
+// agent it does not fully recognize as a bot, Classify reports a bot only
+// for positive bot signals and reports unrecognized clients as
+// ClassUnknown.
+func (ua *UserAgent) Classify() Classification {
+	if strings.TrimSpace(ua.userAgent) == "" {
+		return Classification{Class: ClassBot, Reasons: []Reason{{Kind: ReasonEmpty}}}
+	}
+
+	var bot []Reason
+
+	// The generic bot rule may have matched before an automation tool
+	if ua.botCategory == BotOther || ua.botCategory == BotAutomation && ua.browserFamily == BrowserBot {
+		bot = append(bot, Reason{Kind: ReasonGenericBot, Detail: ua.browser})
+	} else if ua.botCategory == BotScanner {
+		bot = append(bot, Reason{Kind: ReasonScanner, Detail: ua.browser})
+	} else if ua.botCategory != BotNone && ua.botCategory != BotAutomation {
+		bot = append(bot, Reason{Kind: ReasonBotRule, Detail: ua.browser})
+	}
+
+	if ua.automation != "" {
+		bot = append(bot, Reason{Kind: ReasonAutomation, Detail: ua.automation})
+	}
+
+	for _, threat := range ua.Threats() {
+		bot = append(bot, Reason{Kind: ReasonThreat, Detail: threat.String()})
+	}
+
+	if len(bot) > 0 {
+		return Classification{Class: ClassBot, Reasons: bot}
+	}
+
+	var reasons []Reason
+
+	if ua.browserCheck {
+		reasons = append(reasons, Reason{Kind: ReasonBrowser, Detail: ua.browser})
+	} else {
+		if ua.generic {
+			reasons = append(reasons, Reason{Kind: ReasonGenericClient, Detail: ua.browser})
+		}
+
+		reasons = append(reasons, Reason{Kind: ReasonMissingBrowser})
+	}
+
+	if !ua.operatingSystemCheck {
+		reasons = append(reasons, Reason{Kind: ReasonMissingOS})
+	}
+
+	if !ua.deviceCheck {
+		reasons = append(reasons, Reason{Kind: ReasonMissingDevice})
+	}
+
+	anomalies := ua.Anomalies()
+	for _, anomaly := range anomalies {
+		reasons = append(reasons, Reason{Kind: ReasonAnomaly, Detail: anomaly.String()})
+	}
+
+	if ua.browserCheck && ua.operatingSystemCheck && ua.deviceCheck && len(anomalies) == 0 {
+		return Classification{Class: ClassHuman, Reasons: reasons}
+	}
+
+	return Classification{Class: ClassUnknown, Reasons: reasons}
+}
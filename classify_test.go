@@ -0,0 +1,111 @@
+package useragent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		class     Class
+		reasons   []ReasonKind
+	}{
+		{
+			name:      "Chrome on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			class:     ClassHuman,
+			reasons:   []ReasonKind{ReasonBrowser},
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonBotRule},
+		},
+		{
+			name:      "Googlebot with a browser user agent",
+			userAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonBotRule},
+		},
+		{
+			name:      "generic bot",
+			userAgent: "Mozilla/5.0 (compatible; MyCrawler/1.0)",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonGenericBot},
+		},
+		{
+			name:      "scraping framework",
+			userAgent: "Scrapy/2.11.0 (+https://scrapy.org)",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonGenericBot, ReasonAutomation},
+		},
+		{
+			name:      "headless browser",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonAutomation},
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			class:     ClassBot,
+			reasons:   []ReasonKind{ReasonEmpty},
+		},
+		{
+			name:      "unknown app",
+			userAgent: "MyCompanyApp/4.2.1 (iPhone; iOS 17.1; Scale/3.00)",
+			class:     ClassUnknown,
+			reasons:   []ReasonKind{ReasonGenericClient, ReasonMissingBrowser},
+		},
+		{
+			name:      "unknown app without a platform",
+			userAgent: "MyTool/1.0",
+			class:     ClassUnknown,
+			reasons:   []ReasonKind{ReasonGenericClient, ReasonMissingBrowser, ReasonMissingOS, ReasonMissingDevice},
+		},
+		{
+			name:      "spoofed browser",
+			userAgent: "Mozilla/5.0 (Windows NT 5.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			class:     ClassUnknown,
+			reasons:   []ReasonKind{ReasonBrowser, ReasonAnomaly},
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := Parse(tc.userAgent).Classify()
+			if c.Class != tc.class {
+				t.Errorf("expected class %q, but got %s", tc.class, c)
+			}
+
+			kinds := make([]ReasonKind, 0, len(c.Reasons))
+			for _, r := range c.Reasons {
+				kinds = append(kinds, r.Kind)
+			}
+
+			if !slices.Equal(kinds, tc.reasons) {
+				t.Errorf("expected reasons %v, but got %s", tc.reasons, c)
+			}
+		})
+	}
+}
+
+func TestClassificationString(t *testing.T) {
+	t.Parallel()
+
+	c := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)").Classify()
+	if got, want := c.String(), "bot (bot_rule: [Bot] Googlebot)"; got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+
+	if !c.Has(ReasonBotRule) || c.Has(ReasonMissingOS) {
+		t.Errorf("expected only a bot rule reason, but got %s", c)
+	}
+}
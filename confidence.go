@@ -0,0 +1,145 @@
+package useragent
+
+// Confidence is how a field of a user agent was detected, from a rule that
+// names the value down to an assumption made because nothing was detected.
+type Confidence string
+
+// Confidence levels, from highest to lowest.
+const (
+	// ConfidenceExact is a rule matching the value, such as the Chrome
+	// product or a Googlebot token.
+	ConfidenceExact Confidence = "exact"
+	// ConfidenceHeuristic is a value inferred from indirect signals, such as
+	// a generic "bot" in a product name or a tablet without a tablet token.
+	ConfidenceHeuristic Confidence = "heuristic"
+	// ConfidenceGeneric is a value taken by the generic fallback from the
+	// first product or a comment, see UserAgent.IsGeneric.
+	ConfidenceGeneric Confidence = "generic"
+	// ConfidenceDefault is a default value assumed because nothing was
+	// detected, such as an unknown browser or a desktop device type.
+	ConfidenceDefault Confidence = "default"
+)
+
+// String returns the confidence as a string.
+func (c Confidence) String() string {
+	return string(c)
+}
+
+// AtLeast returns true if c is as confident as other or more.
+func (c Confidence) AtLeast(other Confidence) bool {
+	return c.rank() >= other.rank()
+}
+
+// rank orders the confidence levels, higher is more confident.
+func (c Confidence) rank() int {
+	switch c {
+	case ConfidenceExact:
+		return 3
+	case ConfidenceHeuristic:
+		return 2
+	case ConfidenceGeneric:
+		return 1
+	case ConfidenceDefault:
+		return 0
+	}
+
+	return -1
+}
+
+// AllConfidences returns every confidence level, from highest to lowest.
+func AllConfidences() []Confidence {
+	return []Confidence{
+		ConfidenceExact,
+		ConfidenceHeuristic,
+		ConfidenceGeneric,
+		ConfidenceDefault,
+	}
+}
+
+// FieldConfidence is the confidence of each detected field of a user agent.
+type FieldConfidence struct {
+	// Browser is the confidence of Browser, BrowserFamily and
+	// BrowserVersion.
+	Browser Confidence
+	// OS is the confidence of OSFamily and OSVersion.
+	OS Confidence
+	// Device is the confidence of Device and DeviceModel.
+	Device Confidence
+	// DeviceType is the confidence of DeviceType. A desktop device type is
+	// always a default, since it is assumed when no mobile token is found.
+	DeviceType Confidence
+	// Bot is the confidence of IsBot and BotCategory: exact for known bots
+	// and automation tools, heuristic for generic bots and for browsers
+	// without bot signals, and default when the user agent is assumed to
+	// be a bot because nothing was recognized.
+	Bot Confidence
+}
+
+// Confidence returns the confidence of each detected field, so guesses can
+// be told apart from rule matches.
+func (ua *UserAgent) Confidence() FieldConfidence {
+	return ua.confidence
+}
+
+// detectedConfidence holds what parse found, to decide the confidence of
+// each field.
+type detectedConfidence struct {
+	browserMatched bool
+	// browserCategory is the category of the browser rule that matched,
+	// which the automation check may have replaced on the user agent.
+	browserCategory   BotCategory
+	automationMatched bool
+	generic           bool
+	genericPlatform   bool
+	deviceMatched     bool
+	tablet            bool
+	androidTablet     bool
+	mobile            bool
+}
+
+// fieldConfidence returns the confidence of the fields of ua.
+func (d *detectedConfidence) fieldConfidence(ua *UserAgent) FieldConfidence {
+	c := FieldConfidence{
+		Browser:    ConfidenceDefault,
+		OS:         ConfidenceDefault,
+		Device:     ConfidenceDefault,
+		DeviceType: ConfidenceDefault,
+		Bot:        ConfidenceDefault,
+	}
+
+	switch {
+	case d.browserMatched && d.browserCategory == BotOther:
+		c.Browser = ConfidenceHeuristic
+	case d.browserMatched:
+		c.Browser = ConfidenceExact
+	case d.generic:
+		c.Browser = ConfidenceGeneric
+	}
+
+	if d.deviceMatched {
+		c.OS = ConfidenceExact
+		c.Device = ConfidenceExact
+	}
+
+	if d.genericPlatform {
+		c.OS = ConfidenceGeneric
+	}
+
+	// Desktop is only assumed because no mobile or tablet token was found,
+	// so it keeps the default confidence
+	switch {
+	case d.tablet, d.mobile && !d.androidTablet:
+		c.DeviceType = ConfidenceExact
+	case d.androidTablet:
+		c.DeviceType = ConfidenceHeuristic
+	}
+
+	switch {
+	case d.automationMatched, ua.botCategory != BotNone && ua.botCategory != BotOther:
+		c.Bot = ConfidenceExact
+	case ua.botCategory == BotOther, ua.browserCheck && ua.operatingSystemCheck:
+		c.Bot = ConfidenceHeuristic
+	}
+
+	return c
+}
@@ -0,0 +1,119 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestConfidence(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		want      FieldConfidence
+	}{
+		{
+			name:      "Chrome on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceHeuristic,
+			},
+		},
+		{
+			name:      "desktop without a mobile token is a default",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceHeuristic,
+			},
+		},
+		{
+			name:      "Safari on iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceExact, Bot: ConfidenceHeuristic,
+			},
+		},
+		{
+			name:      "Android tablet without a tablet token",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceHeuristic, Bot: ConfidenceHeuristic,
+			},
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceExact,
+			},
+		},
+		{
+			name:      "automation tool",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.6099.28 Safari/537.36",
+			want: FieldConfidence{
+				Browser: ConfidenceExact, OS: ConfidenceExact, Device: ConfidenceExact,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceExact,
+			},
+		},
+		{
+			name:      "automation tool matched by a generic bot rule",
+			userAgent: "Scrapy/2.11.0 (+https://scrapy.org)",
+			want: FieldConfidence{
+				Browser: ConfidenceHeuristic, OS: ConfidenceDefault, Device: ConfidenceDefault,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceExact,
+			},
+		},
+		{
+			name:      "generic bot",
+			userAgent: "Mozilla/5.0 (compatible; MyCrawler/1.0)",
+			want: FieldConfidence{
+				Browser: ConfidenceHeuristic, OS: ConfidenceDefault, Device: ConfidenceDefault,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceHeuristic,
+			},
+		},
+		{
+			name:      "generic client",
+			userAgent: "MyTool/1.0 (macOS 14.2)",
+			want: FieldConfidence{
+				Browser: ConfidenceGeneric, OS: ConfidenceGeneric, Device: ConfidenceDefault,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceDefault,
+			},
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			want: FieldConfidence{
+				Browser: ConfidenceDefault, OS: ConfidenceDefault, Device: ConfidenceDefault,
+				DeviceType: ConfidenceDefault, Bot: ConfidenceDefault,
+			},
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Parse(tc.userAgent).Confidence(); got != tc.want {
+				t.Errorf("expected %+v, but got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestConfidenceAtLeast(t *testing.T) {
+	t.Parallel()
+
+	levels := AllConfidences()
+	for i, c := range levels {
+		for j, other := range levels {
+			if got, want := c.AtLeast(other), i <= j; got != want {
+				t.Errorf("expected %s.AtLeast(%s) to be %v, but got %v", c, other, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package useragent
+
+import (
+	"regexp"
+)
+
+// CrawlerVariant is a specific crawler of a search engine, such as Googlebot
+// Smartphone or Googlebot-Image, which the bot rules report as a single bot.
+type CrawlerVariant struct {
+	// Name is the name of the variant, e.g. "Googlebot Smartphone".
+	Name string
+	// Operator is the company running the crawler, e.g. "Google".
+	Operator string
+	// RobotsToken is the robots.txt product token the variant obeys.
+	RobotsToken string
+	// Mobile is true if the crawler emulates a mobile device.
+	Mobile bool
+}
+
+// crawlerVariantPattern is a rule of the crawlerVariants table.
+type crawlerVariantPattern struct {
+	name string
+	// mobileName replaces name when the crawler emulates a mobile device
+	mobileName string
+	operator   string
+	regex      *regexp.Regexp
+	token      string
+	// parentToken is used for robots.txt when no group names token
+	parentToken string
+}
+
+func compileCrawlerVariant(name, mobileName, operator, pattern, token, parentToken string) crawlerVariantPattern {
+	return crawlerVariantPattern{
+		name:        name,
+		mobileName:  mobileName,
+		operator:    operator,
+		regex:       regexp.MustCompile(`(?i)` + pattern),
+		token:       token,
+		parentToken: parentToken,
+	}
+}
+
+// CrawlerVariant returns the crawler variant of a Google or Microsoft search
+// engine bot.
+func (ua *UserAgent) CrawlerVariant() (CrawlerVariant, bool) {
+	cp := ua.crawlerVariant()
+	if cp == nil {
+		return CrawlerVariant{}, false
+	}
+
+	mobile := crawlerMobileRegEx.MatchString(ua.userAgent)
+
+	name := cp.name
+	if mobile && cp.mobileName != "" {
+		name = cp.mobileName
+	}
+
+	return CrawlerVariant{
+		Name:        name,
+		Operator:    cp.operator,
+		RobotsToken: cp.token,
+		Mobile:      mobile,
+	}, true
+}
+
+// crawlerVariant returns the matching rule of the crawlerVariants table, or
+// nil if there is none.
+func (ua *UserAgent) crawlerVariant() *crawlerVariantPattern {
+	if ua.botCategory != BotSearchEngine {
+		return nil
+	}
+
+	for i := range crawlerVariants {
+		if crawlerVariants[i].regex.MatchString(ua.userAgent) {
+			return &crawlerVariants[i]
+		}
+	}
+
+	return nil
+}
+
+var (
+	crawlerMobileRegEx = regexp.MustCompile(`(?i)mobile|android|iphone`)
+
+	// crawlerVariants is checked in order, so more specific tokens such as
+	// Googlebot-Image come before Googlebot.
+	crawlerVariants = [...]crawlerVariantPattern{
+		// Google
+		compileCrawlerVariant("Google-InspectionTool", "", "Google", `google-inspectiontool`, "Googlebot", ""),
+		compileCrawlerVariant("GoogleOther-Image", "", "Google", `googleother-image`, "GoogleOther-Image", "GoogleOther"),
+		compileCrawlerVariant("GoogleOther-Video", "", "Google", `googleother-video`, "GoogleOther-Video", "GoogleOther"),
+		compileCrawlerVariant("GoogleOther", "", "Google", `googleother`, "GoogleOther", ""),
+		compileCrawlerVariant("Googlebot-Image", "", "Google", `googlebot-image`, "Googlebot-Image", "Googlebot"),
+		compileCrawlerVariant("Googlebot-Video", "", "Google", `googlebot-video`, "Googlebot-Video", "Googlebot"),
+		compileCrawlerVariant("Googlebot-News", "", "Google", `googlebot-news`, "Googlebot-News", "Googlebot"),
+		compileCrawlerVariant("Storebot-Google", "", "Google", `storebot-google`, "Storebot-Google", ""),
+		compileCrawlerVariant("AdsBot-Google-Mobile", "", "Google", `adsbot-google-mobile`, "AdsBot-Google-Mobile", ""),
+		compileCrawlerVariant("AdsBot-Google", "", "Google", `adsbot-google`, "AdsBot-Google", ""),
+		compileCrawlerVariant("Mediapartners-Google", "", "Google", `mediapartners-google`, "Mediapartners-Google", ""),
+		compileCrawlerVariant("APIs-Google", "", "Google", `apis-google`, "APIs-Google", ""),
+		compileCrawlerVariant("FeedFetcher-Google", "", "Google", `feedfetcher-google`, "FeedFetcher-Google", ""),
+		compileCrawlerVariant("Googlebot Desktop", "Googlebot Smartphone", "Google", `googlebot`, "Googlebot", ""),
+		// Microsoft
+		compileCrawlerVariant("BingPreview", "", "Microsoft", `bingpreview`, "bingbot", ""),
+		compileCrawlerVariant("AdIdxBot", "", "Microsoft", `adidxbot`, "adidxbot", "bingbot"),
+		compileCrawlerVariant("MicrosoftPreview", "", "Microsoft", `microsoftpreview`, "MicrosoftPreview", "bingbot"),
+		compileCrawlerVariant("Bingbot Desktop", "Bingbot Mobile", "Microsoft", `bingbot`, "bingbot", ""),
+		compileCrawlerVariant("msnbot-media", "", "Microsoft", `msnbot-media`, "msnbot-media", "msnbot"),
+		compileCrawlerVariant("msnbot", "", "Microsoft", `msnbot`, "msnbot", ""),
+	}
+)
@@ -0,0 +1,232 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestCrawlerVariant(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		variant   string
+		operator  string
+		token     string
+		mobile    bool
+	}{
+		{
+			name:      "Googlebot Desktop",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/141.0.7390.122 Safari/537.36",
+			variant:   "Googlebot Desktop",
+			operator:  "Google",
+			token:     "Googlebot",
+		},
+		{
+			name:      "Googlebot legacy",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			variant:   "Googlebot Desktop",
+			operator:  "Google",
+			token:     "Googlebot",
+		},
+		{
+			name: "Googlebot Smartphone",
+			userAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/141.0.7390.122 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			variant:  "Googlebot Smartphone",
+			operator: "Google",
+			token:    "Googlebot",
+			mobile:   true,
+		},
+		{
+			name:      "Googlebot-Image",
+			userAgent: "Googlebot-Image/1.0",
+			variant:   "Googlebot-Image",
+			operator:  "Google",
+			token:     "Googlebot-Image",
+		},
+		{
+			name:      "Googlebot-Video",
+			userAgent: "Googlebot-Video/1.0",
+			variant:   "Googlebot-Video",
+			operator:  "Google",
+			token:     "Googlebot-Video",
+		},
+		{
+			name:      "Googlebot-News",
+			userAgent: "Googlebot-News",
+			variant:   "Googlebot-News",
+			operator:  "Google",
+			token:     "Googlebot-News",
+		},
+		{
+			name:      "AdsBot-Google",
+			userAgent: "AdsBot-Google (+http://www.google.com/adsbot.html)",
+			variant:   "AdsBot-Google",
+			operator:  "Google",
+			token:     "AdsBot-Google",
+		},
+		{
+			name: "AdsBot-Google-Mobile",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_7_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+				"Version/14.1.2 Mobile/15E148 Safari/604.1 (compatible; AdsBot-Google-Mobile; +http://www.google.com/mobile/adsbot.html)",
+			variant:  "AdsBot-Google-Mobile",
+			operator: "Google",
+			token:    "AdsBot-Google-Mobile",
+			mobile:   true,
+		},
+		{
+			name:      "Mediapartners-Google",
+			userAgent: "Mediapartners-Google",
+			variant:   "Mediapartners-Google",
+			operator:  "Google",
+			token:     "Mediapartners-Google",
+		},
+		{
+			name: "Storebot-Google",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; Storebot-Google/1.0) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/79.0.3945.88 Safari/537.36",
+			variant:  "Storebot-Google",
+			operator: "Google",
+			token:    "Storebot-Google",
+		},
+		{
+			name: "Google-InspectionTool",
+			userAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/141.0.7390.122 Mobile Safari/537.36 (compatible; Google-InspectionTool/1.0;)",
+			variant:  "Google-InspectionTool",
+			operator: "Google",
+			token:    "Googlebot",
+			mobile:   true,
+		},
+		{
+			name:      "GoogleOther",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GoogleOther) Chrome/141.0.7390.122 Safari/537.36",
+			variant:   "GoogleOther",
+			operator:  "Google",
+			token:     "GoogleOther",
+		},
+		{
+			name:      "FeedFetcher-Google",
+			userAgent: "FeedFetcher-Google; (+http://www.google.com/feedfetcher.html)",
+			variant:   "FeedFetcher-Google",
+			operator:  "Google",
+			token:     "FeedFetcher-Google",
+		},
+		{
+			name:      "Bingbot Desktop",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm) Chrome/116.0.1938.76 Safari/537.36",
+			variant:   "Bingbot Desktop",
+			operator:  "Microsoft",
+			token:     "bingbot",
+		},
+		{
+			name: "Bingbot Mobile",
+			userAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/116.0.1938.76 Mobile Safari/537.36 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+			variant:  "Bingbot Mobile",
+			operator: "Microsoft",
+			token:    "bingbot",
+			mobile:   true,
+		},
+		{
+			name: "BingPreview",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/51.0.2704.79 Safari/537.36 Edge/14.14393 BingPreview/1.0b",
+			variant:  "BingPreview",
+			operator: "Microsoft",
+			token:    "bingbot",
+		},
+		{
+			name:      "AdIdxBot",
+			userAgent: "Mozilla/5.0 (compatible; adidxbot/2.0; +http://www.bing.com/bingbot.htm)",
+			variant:   "AdIdxBot",
+			operator:  "Microsoft",
+			token:     "adidxbot",
+		},
+		{
+			name:      "MicrosoftPreview",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; MicrosoftPreview/2.0; +https://aka.ms/MicrosoftPreview) Chrome/116.0.0.0 Safari/537.36",
+			variant:   "MicrosoftPreview",
+			operator:  "Microsoft",
+			token:     "MicrosoftPreview",
+		},
+		{
+			name:      "msnbot",
+			userAgent: "msnbot/2.0b (+http://search.msn.com/msnbot.htm)",
+			variant:   "msnbot",
+			operator:  "Microsoft",
+			token:     "msnbot",
+		},
+		{
+			name:      "Not a crawler variant",
+			userAgent: "Mozilla/5.0 (compatible; YandexBot/3.0; +http://yandex.com/bots)",
+		},
+		{
+			name:      "Browser",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+
+			variant, ok := ua.CrawlerVariant()
+			if ok != (tc.variant != "") {
+				t.Fatalf("expected crawler variant %q, but got %+v", tc.variant, variant)
+			}
+
+			if !ok {
+				return
+			}
+
+			if ua.IsValid() || ua.BotCategory() != BotSearchEngine {
+				t.Errorf("expected a search engine bot, but got %q in category %q", ua.Browser(), ua.BotCategory())
+			}
+
+			if variant.Name != tc.variant {
+				t.Errorf("expected variant %q, but got %q", tc.variant, variant.Name)
+			}
+
+			if variant.Operator != tc.operator {
+				t.Errorf("expected operator %q, but got %q", tc.operator, variant.Operator)
+			}
+
+			if variant.RobotsToken != tc.token || ua.RobotsToken() != tc.token {
+				t.Errorf("expected robots token %q, but got %q and %q", tc.token, variant.RobotsToken, ua.RobotsToken())
+			}
+
+			if variant.Mobile != tc.mobile {
+				t.Errorf("expected mobile %t, but got %t", tc.mobile, variant.Mobile)
+			}
+		})
+	}
+}
+
+func TestCrawlerVariantRobots(t *testing.T) {
+	t.Parallel()
+
+	robots := ParseRobots([]byte("User-agent: Googlebot\nDisallow: /private/\n\nUser-agent: Googlebot-Image\nDisallow: /images/\n\nUser-agent: *\nDisallow: /\n"))
+
+	image := Parse("Googlebot-Image/1.0")
+	if robots.Allowed(image, "/images/cat.png") {
+		t.Error("expected Googlebot-Image to use its own group")
+	}
+
+	if !robots.Allowed(image, "/private/page") {
+		t.Error("expected Googlebot-Image not to combine the Googlebot group")
+	}
+
+	news := Parse("Googlebot-News")
+	if robots.Allowed(news, "/private/page") || !robots.Allowed(news, "/page") {
+		t.Error("expected Googlebot-News to fall back to the Googlebot group")
+	}
+
+	ads := Parse("AdsBot-Google (+http://www.google.com/adsbot.html)")
+	if robots.Allowed(ads, "/page") {
+		t.Error("expected AdsBot-Google to use the * group")
+	}
+}
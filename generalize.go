@@ -0,0 +1,113 @@
+package useragent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Frozen platform versions of the reduced user agent, the values Chrome has
+// sent since the user agent reduction regardless of the real version.
+const (
+	reducedWindowsVersion  = "10.0"
+	reducedMacOSVersion    = "10.15.7"
+	reducedAndroidVersion  = "10"
+	reducedChromeOSVersion = "14541.0.0"
+)
+
+// Generalized is the coarse form of a user agent: browser and operating
+// system families with their major versions, and the device type. It keeps
+// enough for aggregate statistics while most user agents share it with many
+// others, so it can be stored where the full string cannot.
+type Generalized struct {
+	Browser BrowserFamily
+	// BrowserMajor is the major browser version, or 0 if it is unknown.
+	BrowserMajor int
+	OS           OSFamily
+	// OSMajor is the major operating system version, or 0 if it is
+	// unknown. Windows 10 and 11 both report 10.
+	OSMajor    int
+	DeviceType DeviceType
+	// BotCategory is the kind of bot, or BotNone for browsers.
+	BotCategory BotCategory
+}
+
+// Generalize returns the coarse form of the user agent.
+func (ua *UserAgent) Generalize() Generalized {
+	return Generalized{
+		Browser:      ua.browserFamily,
+		BrowserMajor: max(ua.browserVersion.Major(), 0),
+		OS:           ua.operatingSystem,
+		OSMajor:      max(ua.osVersion.Major(), 0),
+		DeviceType:   ua.deviceType,
+		BotCategory:  ua.botCategory,
+	}
+}
+
+// String returns the canonical form "browser/major;os/major;device_type",
+// such as "Chrome/120;windows/10;desktop", followed by ";bot_category" for
+// bots. Unknown versions are left out. Equal generalized user agents always
+// give the same string, so it can be counted and grouped on.
+func (g Generalized) String() string {
+	var b strings.Builder
+
+	b.WriteString(g.Browser.String())
+
+	if g.BrowserMajor > 0 {
+		b.WriteString("/" + strconv.Itoa(g.BrowserMajor))
+	}
+
+	b.WriteString(";" + g.OS.String())
+
+	if g.OSMajor > 0 {
+		b.WriteString("/" + strconv.Itoa(g.OSMajor))
+	}
+
+	b.WriteString(";" + g.DeviceType.String())
+
+	if g.BotCategory != BotNone {
+		b.WriteString(";" + g.BotCategory.String())
+	}
+
+	return b.String()
+}
+
+// Reduced returns a user agent in the frozen format of Chrome's user agent
+// reduction: the browser version is cut to its major version and the
+// Windows, macOS, Android and Chrome OS versions and the Android device model
+// are replaced with fixed values. Parsing it gives back the same browser,
+// operating system and device type. Browsers and platforms the Builder does
+// not support return its errors.
+func (g Generalized) Reduced() (string, error) {
+	if g.BrowserMajor == 0 {
+		return "", fmt.Errorf("%w: browser version is required", ErrMissingVersion)
+	}
+
+	b := Builder{Browser: g.Browser, OS: g.OS, DeviceType: g.DeviceType}
+
+	// Chromium browsers send four version components, the others two
+	b.BrowserVersion = strconv.Itoa(g.BrowserMajor) + ".0"
+	if g.Browser == BrowserChrome || g.Browser == BrowserEdge || g.Browser == BrowserOpera {
+		b.BrowserVersion += ".0.0"
+	}
+
+	switch g.OS {
+	case OSWindows:
+		b.OSVersion = reducedWindowsVersion
+	case OSMacOS:
+		b.OSVersion = reducedMacOSVersion
+	case OSAndroid:
+		b.OSVersion = reducedAndroidVersion
+	case OSChromeOS:
+		b.OSVersion = reducedChromeOSVersion
+	case OSIOS:
+		if g.OSMajor == 0 {
+			return "", fmt.Errorf("%w: %s version is required", ErrMissingVersion, g.OS)
+		}
+
+		b.OSVersion = strconv.Itoa(g.OSMajor) + ".0"
+	case OSLinux, OSUbuntu, OSSuse, OSRedhat, OSFedora, OSCentOS, OSBlackBerry, OSQNX, OSBeOS, OSOS2, OSBot, OSUnknown:
+	}
+
+	return b.Build()
+}
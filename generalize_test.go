@@ -0,0 +1,113 @@
+package useragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeneralize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userAgent   string
+		generalized string
+		reduced     string
+	}{
+		{
+			name:        "Chrome on Windows",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Safari/537.36",
+			generalized: "Chrome/120;windows/10;desktop",
+			reduced:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:        "Edge on macOS",
+			userAgent:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2_1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Safari/537.36 Edg/120.0.2210.61",
+			generalized: "Edge/120;macos/14;desktop",
+			reduced:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+		},
+		{
+			name:        "Chrome on Android",
+			userAgent:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			generalized: "Chrome/120;android/14;mobile",
+			reduced:     "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+		},
+		{
+			name:        "Chrome on an Android tablet",
+			userAgent:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Safari/537.36",
+			generalized: "Chrome/120;android/13;tablet",
+			reduced:     "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:        "Chrome on Chrome OS",
+			userAgent:   "Mozilla/5.0 (X11; CrOS x86_64 15633.69.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			generalized: "Chrome/120;chromeos/15633;desktop",
+			reduced:     "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name:        "Opera on Windows",
+			userAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+			generalized: "Opera/106;windows/10;desktop",
+			reduced:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+		},
+		{
+			name:        "Safari on iOS",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			generalized: "Safari/17;ios/17;mobile",
+			reduced:     "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+		},
+		{
+			name:        "Firefox on Linux",
+			userAgent:   "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			generalized: "Firefox/121;linux;desktop",
+			reduced:     "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := Parse(tc.userAgent).Generalize()
+			if g.String() != tc.generalized {
+				t.Errorf("expected %q, but got %q", tc.generalized, g.String())
+			}
+
+			reduced, err := g.Reduced()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if reduced != tc.reduced {
+				t.Errorf("expected reduced user agent %q, but got %q", tc.reduced, reduced)
+			}
+
+			back := Parse(reduced).Generalize()
+			if back.Browser != g.Browser || back.BrowserMajor != g.BrowserMajor || back.OS != g.OS || back.DeviceType != g.DeviceType {
+				t.Errorf("expected the reduced user agent to parse as %s, but got %s", g, back)
+			}
+		})
+	}
+}
+
+func TestGeneralizeBot(t *testing.T) {
+	t.Parallel()
+
+	g := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)").Generalize()
+	if got, want := g.String(), "bot;bot;desktop;search_engine"; got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+
+	if _, err := g.Reduced(); !errors.Is(err, ErrMissingVersion) {
+		t.Errorf("expected ErrMissingVersion, but got %v", err)
+	}
+}
+
+func TestGeneralizeUnsupported(t *testing.T) {
+	t.Parallel()
+
+	g := Generalized{Browser: BrowserSamsungInternet, BrowserMajor: 23, OS: OSAndroid, OSMajor: 14, DeviceType: DeviceTypeMobile}
+	if _, err := g.Reduced(); !errors.Is(err, ErrUnsupportedBrowser) {
+		t.Errorf("expected ErrUnsupportedBrowser, but got %v", err)
+	}
+}
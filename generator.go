@@ -0,0 +1,351 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// ErrInvalidGeneratorConfig is returned by NewGenerator for configurations
+// that can produce user agents which do not parse as valid.
+var ErrInvalidGeneratorConfig = errors.New("useragent: invalid generator config")
+
+// Weighted is a value with a relative weight. Values are picked with a
+// probability proportional to their weight.
+type Weighted[T any] struct {
+	Value  T
+	Weight float64
+}
+
+// Platform is a combination of browser, operating system and device type.
+type Platform struct {
+	Browser    BrowserFamily
+	OS         OSFamily
+	DeviceType DeviceType
+}
+
+// GeneratorConfig holds the weights a Generator samples from.
+type GeneratorConfig struct {
+	// Platforms are the browser, operating system and device type
+	// combinations to generate.
+	Platforms []Weighted[Platform]
+	// BrowserVersions are the versions of each browser family.
+	BrowserVersions map[BrowserFamily][]Weighted[string]
+	// OSVersions are the versions of each operating system. Operating
+	// systems without versions, such as Linux, may be left out.
+	OSVersions map[OSFamily][]Weighted[string]
+	// DeviceModels are the Android device models of each device type, so
+	// tablets do not name phone models. iOS models follow from the device
+	// type.
+	DeviceModels map[DeviceType][]Weighted[string]
+}
+
+// Generator produces random, realistic user agent strings. Every user agent
+// it returns parses with IsValid true. A Generator is not safe for
+// concurrent use.
+type Generator struct {
+	rng    *rand.Rand
+	config GeneratorConfig
+}
+
+// NewGenerator returns a Generator that samples from config using src. A nil
+// config uses DefaultGeneratorConfig, and a nil src uses a randomly seeded
+// source. Pass a seeded source such as rand.NewPCG(1, 2) for reproducible
+// output.
+func NewGenerator(src rand.Source, config *GeneratorConfig) (*Generator, error) {
+	if src == nil {
+		src = rand.NewPCG(rand.Uint64(), rand.Uint64())
+	}
+
+	var cfg GeneratorConfig
+	if config == nil {
+		cfg = DefaultGeneratorConfig()
+	} else {
+		cfg = config.clone()
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Generator{rng: rand.New(src), config: cfg}, nil
+}
+
+// Builder returns a random user agent description.
+func (g *Generator) Builder() Builder {
+	platform := pickWeighted(g.rng, g.config.Platforms)
+
+	b := Builder{
+		Browser:        platform.Browser,
+		BrowserVersion: pickWeighted(g.rng, g.config.BrowserVersions[platform.Browser]),
+		OS:             platform.OS,
+		OSVersion:      pickWeighted(g.rng, g.config.OSVersions[platform.OS]),
+		DeviceType:     platform.DeviceType,
+	}
+
+	if b.OS == OSAndroid {
+		b.DeviceModel = pickWeighted(g.rng, g.config.DeviceModels[platform.DeviceType])
+	}
+
+	// Safari on iOS always has the version of the operating system
+	if b.Browser == BrowserSafari && b.OS == OSIOS {
+		b.BrowserVersion = b.OSVersion
+	}
+
+	return b
+}
+
+// UserAgent returns a random user agent string.
+func (g *Generator) UserAgent() string {
+	return g.Builder().String()
+}
+
+// clone returns a deep copy of the config, so changes the caller makes to
+// the config after NewGenerator do not reach the Generator.
+func (c *GeneratorConfig) clone() GeneratorConfig {
+	return GeneratorConfig{
+		Platforms:       slices.Clone(c.Platforms),
+		BrowserVersions: cloneWeightedMap(c.BrowserVersions),
+		OSVersions:      cloneWeightedMap(c.OSVersions),
+		DeviceModels:    cloneWeightedMap(c.DeviceModels),
+	}
+}
+
+// cloneWeightedMap returns a copy of m with copies of its slices.
+func cloneWeightedMap[K comparable](m map[K][]Weighted[string]) map[K][]Weighted[string] {
+	if m == nil {
+		return nil
+	}
+
+	clone := make(map[K][]Weighted[string], len(m))
+	for key, values := range m {
+		clone[key] = slices.Clone(values)
+	}
+
+	return clone
+}
+
+// validate checks that every combination the config can produce builds and
+// parses as a valid user agent.
+func (c *GeneratorConfig) validate() error {
+	if totalWeight(c.Platforms) <= 0 {
+		return fmt.Errorf("%w: no platforms with a positive weight", ErrInvalidGeneratorConfig)
+	}
+
+	for _, p := range c.Platforms {
+		if p.Weight < 0 {
+			return fmt.Errorf("%w: negative weight for %s on %s", ErrInvalidGeneratorConfig, p.Value.Browser, p.Value.OS)
+		}
+
+		if p.Weight == 0 {
+			continue
+		}
+
+		if err := c.validatePlatform(p.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePlatform checks every version and model combination of a platform.
+func (c *GeneratorConfig) validatePlatform(platform Platform) error {
+	browserVersions := weightedValues(c.BrowserVersions[platform.Browser])
+	osVersions := weightedValues(c.OSVersions[platform.OS])
+	models := []string{""}
+
+	if platform.OS == OSAndroid {
+		models = weightedValues(c.DeviceModels[platform.DeviceType])
+	}
+
+	for _, browserVersion := range browserVersions {
+		for _, osVersion := range osVersions {
+			for _, model := range models {
+				b := Builder{
+					Browser:        platform.Browser,
+					BrowserVersion: browserVersion,
+					OS:             platform.OS,
+					OSVersion:      osVersion,
+					DeviceModel:    model,
+					DeviceType:     platform.DeviceType,
+				}
+				if b.Browser == BrowserSafari && b.OS == OSIOS {
+					b.BrowserVersion = b.OSVersion
+				}
+
+				userAgent, err := b.Build()
+				if err != nil {
+					return fmt.Errorf("%w: %w", ErrInvalidGeneratorConfig, err)
+				}
+
+				if !Parse(userAgent).IsValid() {
+					return fmt.Errorf("%w: %q is not a valid user agent", ErrInvalidGeneratorConfig, userAgent)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// pickWeighted returns a random value from items, or the zero value if there
+// are no items with a positive weight.
+func pickWeighted[T any](rng *rand.Rand, items []Weighted[T]) T {
+	var zero T
+
+	total := totalWeight(items)
+	if total <= 0 {
+		return zero
+	}
+
+	n := rng.Float64() * total
+	for _, item := range items {
+		if item.Weight <= 0 {
+			continue
+		}
+
+		n -= item.Weight
+		if n < 0 {
+			return item.Value
+		}
+	}
+
+	// Rounding can leave n just above zero, fall back to the last candidate
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Weight > 0 {
+			return items[i].Value
+		}
+	}
+
+	return zero
+}
+
+// totalWeight returns the sum of the positive weights of items.
+func totalWeight[T any](items []Weighted[T]) float64 {
+	total := 0.0
+
+	for _, item := range items {
+		if item.Weight > 0 {
+			total += item.Weight
+		}
+	}
+
+	return total
+}
+
+// weightedValues returns the values of items with a positive weight, or a
+// single zero value if there are none.
+func weightedValues[T any](items []Weighted[T]) []T {
+	var values []T
+
+	for _, item := range items {
+		if item.Weight > 0 {
+			values = append(values, item.Value)
+		}
+	}
+
+	if len(values) == 0 {
+		var zero T
+
+		values = append(values, zero)
+	}
+
+	return values
+}
+
+// DefaultGeneratorConfig returns the built-in distribution, which roughly
+// follows the global browser and platform market share.
+func DefaultGeneratorConfig() GeneratorConfig {
+	return GeneratorConfig{
+		Platforms: []Weighted[Platform]{
+			{Value: Platform{BrowserChrome, OSWindows, DeviceTypeDesktop}, Weight: 28},
+			{Value: Platform{BrowserChrome, OSAndroid, DeviceTypeMobile}, Weight: 24},
+			{Value: Platform{BrowserSafari, OSIOS, DeviceTypeMobile}, Weight: 15},
+			{Value: Platform{BrowserEdge, OSWindows, DeviceTypeDesktop}, Weight: 6},
+			{Value: Platform{BrowserChrome, OSMacOS, DeviceTypeDesktop}, Weight: 5},
+			{Value: Platform{BrowserSafari, OSMacOS, DeviceTypeDesktop}, Weight: 4},
+			{Value: Platform{BrowserFirefox, OSWindows, DeviceTypeDesktop}, Weight: 3},
+			{Value: Platform{BrowserChrome, OSIOS, DeviceTypeMobile}, Weight: 2},
+			{Value: Platform{BrowserSafari, OSIOS, DeviceTypeTablet}, Weight: 2},
+			{Value: Platform{BrowserChrome, OSLinux, DeviceTypeDesktop}, Weight: 1.5},
+			{Value: Platform{BrowserChrome, OSAndroid, DeviceTypeTablet}, Weight: 1},
+			{Value: Platform{BrowserChrome, OSChromeOS, DeviceTypeDesktop}, Weight: 1},
+			{Value: Platform{BrowserOpera, OSWindows, DeviceTypeDesktop}, Weight: 1},
+			{Value: Platform{BrowserEdge, OSMacOS, DeviceTypeDesktop}, Weight: 0.7},
+			{Value: Platform{BrowserFirefox, OSMacOS, DeviceTypeDesktop}, Weight: 0.6},
+			{Value: Platform{BrowserFirefox, OSLinux, DeviceTypeDesktop}, Weight: 0.5},
+			{Value: Platform{BrowserFirefox, OSAndroid, DeviceTypeMobile}, Weight: 0.4},
+			{Value: Platform{BrowserEdge, OSAndroid, DeviceTypeMobile}, Weight: 0.3},
+			{Value: Platform{BrowserFirefox, OSIOS, DeviceTypeMobile}, Weight: 0.2},
+			{Value: Platform{BrowserEdge, OSIOS, DeviceTypeMobile}, Weight: 0.1},
+		},
+		BrowserVersions: map[BrowserFamily][]Weighted[string]{
+			BrowserChrome: {
+				{Value: "141.0.0.0", Weight: 55},
+				{Value: "140.0.0.0", Weight: 25},
+				{Value: "139.0.0.0", Weight: 10},
+				{Value: "138.0.0.0", Weight: 5},
+				{Value: "109.0.0.0", Weight: 1},
+			},
+			BrowserEdge: {
+				{Value: "141.0.3537.85", Weight: 60},
+				{Value: "140.0.3485.94", Weight: 30},
+				{Value: "139.0.3405.125", Weight: 10},
+			},
+			BrowserOpera: {
+				{Value: "122.0.0.0", Weight: 60},
+				{Value: "121.0.0.0", Weight: 40},
+			},
+			BrowserFirefox: {
+				{Value: "144.0", Weight: 55},
+				{Value: "143.0", Weight: 25},
+				{Value: "140.0", Weight: 15},
+				{Value: "128.0", Weight: 5},
+			},
+			BrowserSafari: {
+				{Value: "26.0", Weight: 45},
+				{Value: "18.6", Weight: 35},
+				{Value: "17.6", Weight: 20},
+			},
+		},
+		OSVersions: map[OSFamily][]Weighted[string]{
+			OSWindows:  {{Value: "10.0", Weight: 1}},
+			OSMacOS:    {{Value: "10.15.7", Weight: 1}},
+			OSChromeOS: {{Value: "14541.0.0", Weight: 1}},
+			OSAndroid: {
+				{Value: "16", Weight: 15},
+				{Value: "15", Weight: 30},
+				{Value: "14", Weight: 25},
+				{Value: "13", Weight: 15},
+				{Value: "12", Weight: 10},
+				{Value: "10", Weight: 5},
+			},
+			OSIOS: {
+				{Value: "26.0", Weight: 35},
+				{Value: "18.6", Weight: 40},
+				{Value: "18.5", Weight: 10},
+				{Value: "17.6", Weight: 15},
+			},
+		},
+		DeviceModels: map[DeviceType][]Weighted[string]{
+			DeviceTypeMobile: {
+				{Value: "K", Weight: 80},
+				{Value: "Pixel 9", Weight: 4},
+				{Value: "Pixel 8", Weight: 4},
+				{Value: "SM-S928B", Weight: 4},
+				{Value: "SM-A546B", Weight: 4},
+				{Value: "M2101K6G", Weight: 2},
+				{Value: "CPH2581", Weight: 2},
+			},
+			DeviceTypeTablet: {
+				{Value: "K", Weight: 80},
+				{Value: "SM-X710", Weight: 6},
+				{Value: "SM-X210", Weight: 6},
+				{Value: "Pixel Tablet", Weight: 4},
+				{Value: "23043RP34G", Weight: 4},
+			},
+		},
+	}
+}
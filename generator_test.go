@@ -0,0 +1,201 @@
+package useragent
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestGeneratorValid(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewGenerator(rand.NewPCG(1, 2), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	browsers := map[BrowserFamily]int{}
+	seen := map[string]bool{}
+
+	for range 1000 {
+		userAgent := g.UserAgent()
+
+		ua := Parse(userAgent)
+		if !ua.IsValid() {
+			t.Fatalf("expected IsValid() to be true for %q", userAgent)
+		}
+
+		browsers[ua.BrowserFamily()]++
+		seen[userAgent] = true
+	}
+
+	if browsers[BrowserChrome] <= browsers[BrowserFirefox] {
+		t.Errorf("expected Chrome to be more common than Firefox, but got %v", browsers)
+	}
+
+	if len(seen) < 50 {
+		t.Errorf("expected at least 50 distinct user agents, but got %d", len(seen))
+	}
+}
+
+func TestGeneratorReproducible(t *testing.T) {
+	t.Parallel()
+
+	a, err := NewGenerator(rand.NewPCG(42, 7), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := NewGenerator(rand.NewPCG(42, 7), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range 100 {
+		if ua1, ua2 := a.UserAgent(), b.UserAgent(); ua1 != ua2 {
+			t.Fatalf("expected identical output at %d, but got %q and %q", i, ua1, ua2)
+		}
+	}
+}
+
+func TestGeneratorCustomConfig(t *testing.T) {
+	t.Parallel()
+
+	config := &GeneratorConfig{
+		Platforms: []Weighted[Platform]{
+			{Value: Platform{BrowserFirefox, OSLinux, DeviceTypeDesktop}, Weight: 1},
+		},
+		BrowserVersions: map[BrowserFamily][]Weighted[string]{
+			BrowserFirefox: {{Value: "133.0", Weight: 1}},
+		},
+	}
+
+	g, err := NewGenerator(rand.NewPCG(1, 1), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Mozilla/5.0 (X11; Linux x86_64; rv:133.0) Gecko/20100101 Firefox/133.0"
+	if got := g.UserAgent(); got != expected {
+		t.Errorf("expected %q, but got %q", expected, got)
+	}
+}
+
+func TestGeneratorDeviceModels(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewGenerator(rand.NewPCG(3, 4), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	phones := map[string]bool{}
+	for _, model := range DefaultGeneratorConfig().DeviceModels[DeviceTypeMobile] {
+		phones[model.Value] = model.Value != "K"
+	}
+
+	tablets := 0
+
+	for range 2000 {
+		b := g.Builder()
+		if b.OS != OSAndroid || b.DeviceType != DeviceTypeTablet {
+			continue
+		}
+
+		tablets++
+
+		if phones[b.DeviceModel] {
+			t.Errorf("expected a tablet model, but got the phone %q", b.DeviceModel)
+		}
+	}
+
+	if tablets == 0 {
+		t.Error("expected some Android tablets")
+	}
+}
+
+func TestGeneratorCopiesConfig(t *testing.T) {
+	t.Parallel()
+
+	config := &GeneratorConfig{
+		Platforms: []Weighted[Platform]{
+			{Value: Platform{BrowserFirefox, OSLinux, DeviceTypeDesktop}, Weight: 1},
+		},
+		BrowserVersions: map[BrowserFamily][]Weighted[string]{
+			BrowserFirefox: {{Value: "133.0", Weight: 1}},
+		},
+	}
+
+	g, err := NewGenerator(rand.NewPCG(1, 1), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Neither change would pass validation
+	config.Platforms[0].Value.Browser = BrowserSafari
+	config.BrowserVersions[BrowserFirefox][0].Value = "not a version"
+
+	expected := "Mozilla/5.0 (X11; Linux x86_64; rv:133.0) Gecko/20100101 Firefox/133.0"
+	if got := g.UserAgent(); got != expected {
+		t.Errorf("expected %q, but got %q", expected, got)
+	}
+}
+
+func TestGeneratorInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config *GeneratorConfig
+	}{
+		{
+			name:   "no platforms",
+			config: &GeneratorConfig{},
+		},
+		{
+			name: "Safari on Windows",
+			config: &GeneratorConfig{
+				Platforms: []Weighted[Platform]{
+					{Value: Platform{BrowserSafari, OSWindows, DeviceTypeDesktop}, Weight: 1},
+				},
+				BrowserVersions: map[BrowserFamily][]Weighted[string]{
+					BrowserSafari: {{Value: "17.2", Weight: 1}},
+				},
+				OSVersions: map[OSFamily][]Weighted[string]{
+					OSWindows: {{Value: "10.0", Weight: 1}},
+				},
+			},
+		},
+		{
+			name: "missing versions",
+			config: &GeneratorConfig{
+				Platforms: []Weighted[Platform]{
+					{Value: Platform{BrowserChrome, OSLinux, DeviceTypeDesktop}, Weight: 1},
+				},
+			},
+		},
+		{
+			name: "negative weight",
+			config: &GeneratorConfig{
+				Platforms: []Weighted[Platform]{
+					{Value: Platform{BrowserChrome, OSLinux, DeviceTypeDesktop}, Weight: 1},
+					{Value: Platform{BrowserFirefox, OSLinux, DeviceTypeDesktop}, Weight: -1},
+				},
+				BrowserVersions: map[BrowserFamily][]Weighted[string]{
+					BrowserChrome: {{Value: "120.0.0.0", Weight: 1}},
+				},
+			},
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewGenerator(rand.NewPCG(1, 1), tc.config)
+			if !errors.Is(err, ErrInvalidGeneratorConfig) {
+				t.Errorf("expected ErrInvalidGeneratorConfig, but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// genericSkippedProducts are the products user agents send for
+// compatibility, which never name the client.
+var genericSkippedProducts = map[string]bool{
+	"mozilla":     true,
+	"applewebkit": true,
+	"khtml":       true,
+	"gecko":       true,
+	"like":        true,
+	"compatible":  true,
+	"mobile":      true,
+	"version":     true,
+}
+
+// genericPlatformRegEx matches comment fields that name a platform and its
+// version, such as "iOS 17.1" or "Android 14".
+var genericPlatformRegEx = regexp.MustCompile(
+	`(?i)^(ipados|ios|iphone os|cpu os|mac os x|macos|android|windows nt|windows)[ /]v?(\d+(?:[._]\d+)*)`,
+)
+
+// genericPlatforms maps the platforms of genericPlatformRegEx to their
+// operating system.
+var genericPlatforms = map[string]OSFamily{
+	"ipados":     OSIOS,
+	"ios":        OSIOS,
+	"iphone os":  OSIOS,
+	"cpu os":     OSIOS,
+	"mac os x":   OSMacOS,
+	"macos":      OSMacOS,
+	"android":    OSAndroid,
+	"windows nt": OSWindows,
+	"windows":    OSWindows,
+}
+
+// genericProduct returns the first top level product that names the client
+// with a version, such as "MyCompanyApp/4.2.1", or nil if there is none.
+func genericProduct(t *uaTokens) *productToken {
+	for i := range t.products {
+		p := &t.products[i]
+		if p.inComment || p.lowerName == "" || p.version == "" || genericSkippedProducts[p.lowerName] {
+			continue
+		}
+
+		if c := p.lowerName[0]; c < 'a' || c > 'z' {
+			continue
+		}
+
+		return p
+	}
+
+	return nil
+}
+
+// genericPlatform returns the operating system and its version named by the
+// first comment field that names a platform, or OSUnknown if there is none.
+func genericPlatform(t *uaTokens) (OSFamily, Version) {
+	for i := range t.segments {
+		m := genericPlatformRegEx.FindStringSubmatch(t.segments[i].text)
+		if m == nil {
+			continue
+		}
+
+		return genericPlatforms[strings.ToLower(m[1])], parseVersion(strings.ReplaceAll(m[2], "_", "."))
+	}
+
+	return OSUnknown, Version{}
+}
+
+// IsGeneric returns true if no rule recognized the browser and Browser and
+// BrowserVersion are the first product of the user agent, such as
+// "MyCompanyApp" and "4.2.1" for "MyCompanyApp/4.2.1 (iPhone; iOS 17.1)".
+// Generic matches are low confidence: the browser family stays
+// BrowserUnknown and IsBrowserValid returns false.
+func (ua *UserAgent) IsGeneric() bool {
+	return ua.generic
+}
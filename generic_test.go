@@ -0,0 +1,147 @@
+package useragent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenericFallback(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		generic   bool
+		browser   string
+		version   string
+		os        OSFamily
+		osVersion string
+	}{
+		{
+			name:      "iOS app",
+			userAgent: "MyCompanyApp/4.2.1 (iPhone; iOS 17.1; Scale/3.00)",
+			generic:   true,
+			browser:   "MyCompanyApp",
+			version:   "4.2.1",
+			os:        OSIOS,
+			osVersion: "17.1",
+		},
+		{
+			name:      "Android HTTP client",
+			userAgent: "Dalvik/2.1.0 (Linux; U; Android 13; SM-S911B Build/TP1A.220624.014)",
+			generic:   true,
+			browser:   "Dalvik",
+			version:   "2.1.0",
+			os:        OSAndroid,
+			osVersion: "13",
+		},
+		{
+			name:      "platform found only in the comment",
+			userAgent: "MyTool/1.0 (macOS 14.2)",
+			generic:   true,
+			browser:   "MyTool",
+			version:   "1.0",
+			os:        OSMacOS,
+			osVersion: "14.2",
+		},
+		{
+			name:      "compatibility products are skipped",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) SomeApp/3.1",
+			generic:   true,
+			browser:   "SomeApp",
+			version:   "3.1",
+			os:        OSWindows,
+			osVersion: "10.0",
+		},
+		{
+			name:      "product without a version",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) Kiosk",
+			browser:   "unknown",
+			os:        OSLinux,
+		},
+		{
+			name:      "rule match",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			browser:   "Chrome",
+			version:   "120.0.0.0",
+			os:        OSWindows,
+			osVersion: "10.0",
+		},
+		{
+			name:      "automation tool",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; rv:59.0) Gecko/20100101 SlimerJS/1.0.0",
+			browser:   "unknown",
+			os:        OSWindows,
+			osVersion: "10.0",
+		},
+		{
+			name:      "HTTP library",
+			userAgent: "curl/8.4.0",
+			browser:   "curl",
+			generic:   true,
+			version:   "8.4.0",
+			os:        OSUnknown,
+		},
+		{
+			name:      "generic bot",
+			userAgent: "Mozilla/5.0 (compatible; MyCrawler/1.0)",
+			browser:   "[Bot] Other",
+			os:        OSUnknown,
+		},
+		{
+			name:      "product without a name",
+			userAgent: "/1.0",
+			browser:   "unknown",
+			os:        OSUnknown,
+		},
+		{
+			name:      "product without a name after a skipped product",
+			userAgent: "Mozilla/5.0 /2",
+			browser:   "unknown",
+			os:        OSUnknown,
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			browser:   "unknown",
+			os:        OSUnknown,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.IsGeneric() != tc.generic {
+				t.Errorf("expected IsGeneric() to be %v, but got %v", tc.generic, ua.IsGeneric())
+			}
+
+			if ua.Browser() != tc.browser || ua.BrowserVersion().String() != tc.version {
+				t.Errorf("expected browser %q %q, but got %q %q", tc.browser, tc.version, ua.Browser(), ua.BrowserVersion())
+			}
+
+			if ua.OSFamily() != tc.os || ua.OSVersion().String() != tc.osVersion {
+				t.Errorf("expected OS %q %q, but got %q %q", tc.os, tc.osVersion, ua.OSFamily(), ua.OSVersion())
+			}
+
+			if tc.generic && (ua.BrowserFamily() != BrowserUnknown || ua.IsBrowserValid()) {
+				t.Errorf("expected a generic match to have no browser family and not be valid, but got %q %v",
+					ua.BrowserFamily(), ua.IsBrowserValid())
+			}
+		})
+	}
+}
+
+func TestGenericFallbackTrace(t *testing.T) {
+	t.Parallel()
+
+	_, trace := ParseWithTrace("MyCompanyApp/4.2.1 (iPhone; iOS 17.1; Scale/3.00)")
+	if !trace.Generic || trace.Browser.Matched() {
+		t.Fatalf("expected a generic match without a browser rule, but got %+v", trace.Browser)
+	}
+
+	if !strings.Contains(trace.String(), "generic browser") {
+		t.Errorf("expected the trace to mention the generic match:\n%s", trace)
+	}
+}
@@ -0,0 +1,225 @@
+package useragent
+
+// BrowserID is the stable numeric ID of a browser or bot rule, for storing
+// parsed user agents in small integer columns. Zero means the browser is
+// unknown or came from the generic fallback.
+type BrowserID uint16
+
+// OSID is the stable numeric ID of an operating system family. Zero means
+// OSUnknown.
+type OSID uint16
+
+// DeviceID is the stable numeric ID of a device rule. Zero means the device
+// is unknown.
+type DeviceID uint16
+
+// The registries below assign every browser, bot, operating system and
+// device its ID by name, so IDs do not depend on the order of the rule
+// tables and stay the same when rules are inserted or reordered. An ID is
+// never changed or reused: new rules take the next unused number, and the
+// entries of removed rules stay in the registry so their IDs remain
+// reserved. Rules that share a name, such as the two Googlebot rules, share
+// an ID.
+var (
+	browserIDs = map[string]BrowserID{
+		"DuckDuckGo":                    1,
+		"Brave":                         2,
+		"Samsung Internet":              3,
+		"UC Browser":                    4,
+		"Opera Mini":                    5,
+		"Opera Mobile":                  6,
+		"Yandex":                        7,
+		"360 Safe":                      8,
+		"Vivaldi":                       9,
+		"Arc":                           10,
+		"Opera GX":                      11,
+		"Tor Browser":                   12,
+		"Lynx":                          13,
+		"SeaMonkey":                     14,
+		"Pale Moon":                     15,
+		"Midori":                        16,
+		"Avast Secure Browser":          17,
+		"Opera":                         18,
+		"Edge":                          19,
+		"Chrome":                        20,
+		"Firefox":                       21,
+		"Safari":                        22,
+		"Internet Explorer":             23,
+		"[Bot] Googlebot":               24,
+		"[Bot] Bingbot":                 25,
+		"[Bot] Yahoo! Slurp":            26,
+		"[Bot] DuckDuckBot":             27,
+		"[Bot] Baidu":                   28,
+		"[Bot] Yandex":                  29,
+		"[Bot] Sogou":                   30,
+		"[Bot] Exabot":                  31,
+		"[Bot] MSN":                     32,
+		"[Bot] OAI-SearchBot":           33,
+		"[Bot] ChatGPT":                 34,
+		"[Bot] Claude-User":             35,
+		"[Bot] Claude-SearchBot":        36,
+		"[Bot] ClaudeBot":               37,
+		"[Bot] GPTBot":                  38,
+		"[Bot] Perplexity-User":         39,
+		"[Bot] PerplexityBot":           40,
+		"[Bot] CCBot":                   41,
+		"[Bot] Meta-ExternalAgent":      42,
+		"[Bot] Meta-ExternalFetcher":    43,
+		"[Bot] OpenAI":                  44,
+		"[Bot] Facebook":                45,
+		"[Bot] Pinterest":               46,
+		"[Bot] LinkedInBot":             47,
+		"[Bot] Instagram":               48,
+		"[Bot] Twitterbot":              49,
+		"[Bot] Snapchat":                50,
+		"[Bot] Discord":                 51,
+		"[Bot] PetalBot":                52,
+		"[Bot] Applebot":                53,
+		"[Bot] Amazon":                  54,
+		"[Bot] Majestic":                55,
+		"[Bot] Ahrefs":                  56,
+		"[Bot] SEMRush":                 57,
+		"[Bot] Moz or OpenSiteExplorer": 58,
+		"[Bot] Screaming Frog":          59,
+		"[Bot] Pingdom":                 60,
+		"[Bot] Riddler":                 61,
+		"[Bot] W3C Validator":           62,
+		"[Bot] Other":                   63,
+		"[Bot] Bytespider":              64,
+		"[Bot] sqlmap":                  65,
+		"[Bot] Nikto":                   66,
+		"[Bot] Nmap Scripting Engine":   67,
+		"[Bot] masscan":                 68,
+		"[Bot] ZGrab":                   69,
+		"[Bot] Nuclei":                  70,
+		"[Bot] WPScan":                  71,
+		"[Bot] Acunetix":                72,
+		"[Bot] Burp Suite":              73,
+		"[Bot] OpenVAS":                 74,
+		"[Bot] Nessus":                  75,
+		"[Bot] Netsparker":              76,
+		"[Bot] Arachni":                 77,
+		"[Bot] w3af":                    78,
+		"[Bot] commix":                  79,
+		"[Bot] Wfuzz":                   80,
+		"[Bot] DirBuster":               81,
+		"[Bot] gobuster":                82,
+		"[Bot] feroxbuster":             83,
+		"[Bot] ZmEu":                    84,
+		"[Bot] Jorgee":                  85,
+	}
+
+	osIDs = map[OSFamily]OSID{
+		OSWindows:    1,
+		OSLinux:      2,
+		OSMacOS:      3,
+		OSAndroid:    4,
+		OSIOS:        5,
+		OSUbuntu:     6,
+		OSSuse:       7,
+		OSRedhat:     8,
+		OSFedora:     9,
+		OSCentOS:     10,
+		OSChromeOS:   11,
+		OSBlackBerry: 12,
+		OSQNX:        13,
+		OSBeOS:       14,
+		OSOS2:        15,
+		OSBot:        16,
+	}
+
+	deviceIDs = map[string]DeviceID{
+		"Windows 3.11":        1,
+		"Windows 95":          2,
+		"Windows 98":          3,
+		"Windows 2000":        4,
+		"Windows XP":          5,
+		"Windows Server 2003": 6,
+		"Windows Vista":       7,
+		"Windows 7":           8,
+		"Windows 8":           9,
+		"Windows 10":          10,
+		"Windows NT 4.0":      11,
+		"Windows ME":          12,
+		"Windows Phone":       13,
+		"Open BSD":            14,
+		"FreeBSD":             15,
+		"NetBSD":              16,
+		"Solaris":             17,
+		"Android":             18,
+		"Ubuntu":              19,
+		"Suse":                20,
+		"Redhat":              21,
+		"Fedora":              22,
+		"Centos":              23,
+		"Chrome OS":           24,
+		"Linux":               25,
+		"Mac OS":              26,
+		"BlackBerry":          27,
+		"QNX":                 28,
+		"BeOS":                29,
+		"OS/2":                30,
+		"iPod":                31,
+		"iPhone":              32,
+		"iPad":                33,
+		"Search Bot":          34,
+	}
+
+	browserNamesByID = invertIDs(browserIDs)
+	osFamiliesByID   = invertIDs(osIDs)
+	deviceNamesByID  = invertIDs(deviceIDs)
+)
+
+// BrowserID returns the ID of the browser or bot, or 0 if the browser is
+// unknown or generic.
+func (ua *UserAgent) BrowserID() BrowserID {
+	if ua.generic {
+		return 0
+	}
+
+	return browserIDs[ua.browser]
+}
+
+// OSID returns the ID of the operating system family, or 0 if it is unknown.
+func (ua *UserAgent) OSID() OSID {
+	return osIDs[ua.operatingSystem]
+}
+
+// DeviceID returns the ID of the device, or 0 if it is unknown.
+func (ua *UserAgent) DeviceID() DeviceID {
+	return deviceIDs[ua.device]
+}
+
+// BrowserByID returns the name of the browser or bot with the ID, as
+// returned by Browser, and false if the ID is not assigned.
+func BrowserByID(id BrowserID) (string, bool) {
+	name, ok := browserNamesByID[id]
+
+	return name, ok
+}
+
+// OSByID returns the operating system family with the ID, and false if the
+// ID is not assigned.
+func OSByID(id OSID) (OSFamily, bool) {
+	family, ok := osFamiliesByID[id]
+
+	return family, ok
+}
+
+// DeviceByID returns the name of the device with the ID, as returned by
+// Device, and false if the ID is not assigned.
+func DeviceByID(id DeviceID) (string, bool) {
+	name, ok := deviceNamesByID[id]
+
+	return name, ok
+}
+
+// invertIDs returns the reverse lookup of a registry.
+func invertIDs[K comparable, V comparable](ids map[K]V) map[V]K {
+	inverted := make(map[V]K, len(ids))
+	for k, v := range ids {
+		inverted[v] = k
+	}
+
+	return inverted
+}
@@ -0,0 +1,108 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestIDsCoverTables(t *testing.T) {
+	t.Parallel()
+
+	for i := range browsers {
+		if browserIDs[browsers[i].name] == 0 {
+			t.Errorf("expected an ID for the browsers rule %q", browsers[i].name)
+		}
+	}
+
+	for i := range devices {
+		if deviceIDs[devices[i].name] == 0 {
+			t.Errorf("expected an ID for the devices rule %q", devices[i].name)
+		}
+	}
+
+	for _, family := range AllOSFamilies() {
+		if family != OSUnknown && osIDs[family] == 0 {
+			t.Errorf("expected an ID for the OS family %q", family)
+		}
+	}
+
+	if _, ok := osIDs[OSUnknown]; ok {
+		t.Errorf("expected OSUnknown to have ID 0")
+	}
+}
+
+func TestIDsUnique(t *testing.T) {
+	t.Parallel()
+
+	if len(browserNamesByID) != len(browserIDs) {
+		t.Errorf("expected %d browser IDs, but got %d, an ID is assigned twice", len(browserIDs), len(browserNamesByID))
+	}
+
+	if len(osFamiliesByID) != len(osIDs) {
+		t.Errorf("expected %d OS IDs, but got %d, an ID is assigned twice", len(osIDs), len(osFamiliesByID))
+	}
+
+	if len(deviceNamesByID) != len(deviceIDs) {
+		t.Errorf("expected %d device IDs, but got %d, an ID is assigned twice", len(deviceIDs), len(deviceNamesByID))
+	}
+
+	if _, ok := browserNamesByID[0]; ok {
+		t.Errorf("expected browser ID 0 to be unassigned")
+	}
+
+	if _, ok := deviceNamesByID[0]; ok {
+		t.Errorf("expected device ID 0 to be unassigned")
+	}
+}
+
+func TestIDs(t *testing.T) {
+	// IDs are stored by users and must never change
+	testCases := []struct {
+		userAgent string
+		browser   BrowserID
+		os        OSID
+		device    DeviceID
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", 20, 1, 10},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1", 22, 5, 32},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", 24, 16, 34},
+		{"sqlmap/1.7.2#stable (https://sqlmap.org)", 65, 0, 0},
+		{"MyCompanyApp/4.2.1 (iPhone; iOS 17.1; Scale/3.00)", 0, 5, 32},
+		{"", 0, 0, 0},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		ua := Parse(tc.userAgent)
+		if ua.BrowserID() != tc.browser || ua.OSID() != tc.os || ua.DeviceID() != tc.device {
+			t.Errorf("expected IDs %d, %d, %d for %q, but got %d, %d, %d",
+				tc.browser, tc.os, tc.device, tc.userAgent, ua.BrowserID(), ua.OSID(), ua.DeviceID())
+		}
+
+		if tc.browser != 0 {
+			if name, ok := BrowserByID(tc.browser); !ok || name != ua.Browser() {
+				t.Errorf("expected browser %d to be %q, but got %q", tc.browser, ua.Browser(), name)
+			}
+		}
+
+		if tc.os != 0 {
+			if family, ok := OSByID(tc.os); !ok || family != ua.OSFamily() {
+				t.Errorf("expected OS %d to be %q, but got %q", tc.os, ua.OSFamily(), family)
+			}
+		}
+
+		if tc.device != 0 {
+			if name, ok := DeviceByID(tc.device); !ok || name != ua.Device() {
+				t.Errorf("expected device %d to be %q, but got %q", tc.device, ua.Device(), name)
+			}
+		}
+	}
+
+	if _, ok := BrowserByID(0); ok {
+		t.Errorf("expected browser ID 0 to be unassigned")
+	}
+
+	if _, ok := OSByID(1000); ok {
+		t.Errorf("expected OS ID 1000 to be unassigned")
+	}
+}
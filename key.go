@@ -0,0 +1,88 @@
+package useragent
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Key identifies the type of client that sent a user agent, ignoring build
+// numbers, locales and other details that make almost every user agent
+// string unique. Keys are comparable and can be used as map keys.
+type Key struct {
+	// Browser is the browser family, BrowserBot for bots.
+	Browser BrowserFamily
+	// Product is the lowercase name of a bot or of an unknown browser, such
+	// as the product of a generic match, or an empty string.
+	Product string
+	// BrowserMajor is the major browser version, or 0 if it is unknown.
+	BrowserMajor int
+	OS           OSFamily
+	// OSMajor is the major operating system version, or 0 if it is
+	// unknown.
+	OSMajor int
+	// DeviceModel is the lowercase device model with runs of spaces
+	// collapsed, or an empty string if the user agent does not name one.
+	DeviceModel string
+	DeviceType  DeviceType
+}
+
+// Key returns the grouping key of the user agent.
+func (ua *UserAgent) Key() Key {
+	key := Key{
+		Browser:      ua.browserFamily,
+		BrowserMajor: max(ua.browserVersion.Major(), 0),
+		OS:           ua.operatingSystem,
+		OSMajor:      max(ua.osVersion.Major(), 0),
+		DeviceModel:  normalizeDeviceModel(ua.deviceModel),
+		DeviceType:   ua.deviceType,
+	}
+
+	if ua.browserFamily == BrowserBot || ua.browserFamily == BrowserUnknown {
+		key.Product = normalizeKeyField(ua.browser)
+	}
+
+	return key
+}
+
+// Hash returns a 64-bit FNV-1a hash of the key. The encoding it hashes is
+// fixed, so equal keys give the same hash in every version of the library
+// and on every platform, and hashes can be stored.
+func (k Key) Hash() uint64 {
+	h := fnv.New64a()
+
+	// The fields are separated by NUL bytes, which normalized fields never
+	// contain
+	for _, field := range [...]string{
+		k.Browser.String(),
+		k.Product,
+		strconv.Itoa(k.BrowserMajor),
+		k.OS.String(),
+		strconv.Itoa(k.OSMajor),
+		k.DeviceModel,
+		k.DeviceType.String(),
+	} {
+		_, _ = h.Write([]byte(field))
+		_, _ = h.Write([]byte{0})
+	}
+
+	return h.Sum64()
+}
+
+// normalizeKeyField lowercases s, collapses runs of whitespace into a single
+// space and removes NUL bytes.
+func normalizeKeyField(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(s, "\x00", ""))), " ")
+}
+
+// normalizeDeviceModel normalizes a device model like normalizeKeyField and
+// drops the "K" that Chrome on Android sends instead of the model since the
+// user agent reduction, so it does not look like a device.
+func normalizeDeviceModel(model string) string {
+	model = normalizeKeyField(model)
+	if model == "k" {
+		return ""
+	}
+
+	return model
+}
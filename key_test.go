@@ -0,0 +1,122 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{
+			name:     "build numbers",
+			a:        "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			b:        "Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/UD1A.230803.041) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.230 Mobile Safari/537.36",
+			expected: true,
+		},
+		{
+			name:     "locale",
+			a:        "Mozilla/5.0 (Windows; U; Windows NT 6.1; en-US; rv:1.9.2.13) Gecko/20101203 Firefox/3.6.13",
+			b:        "Mozilla/5.0 (Windows; U; Windows NT 6.1; de-DE; rv:1.9.2.28) Gecko/20120306 Firefox/3.6.28",
+			expected: true,
+		},
+		{
+			name:     "major version",
+			a:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			b:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36",
+			expected: false,
+		},
+		{
+			name:     "device model",
+			a:        "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			b:        "Mozilla/5.0 (Linux; Android 14; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			expected: false,
+		},
+		{
+			name:     "browser",
+			a:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			b:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			expected: false,
+		},
+		{
+			name:     "generic product",
+			a:        "MyApp/1.0 (Linux)",
+			b:        "OtherApp/1.0 (Linux)",
+			expected: false,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := Parse(tc.a).Key(), Parse(tc.b).Key()
+			if (a == b) != tc.expected {
+				t.Errorf("expected equal keys to be %v, but got %+v and %+v", tc.expected, a, b)
+			}
+
+			if (a.Hash() == b.Hash()) != tc.expected {
+				t.Errorf("expected equal hashes to be %v, but got %d and %d", tc.expected, a.Hash(), b.Hash())
+			}
+		})
+	}
+}
+
+func TestKeyFields(t *testing.T) {
+	t.Parallel()
+
+	key := Parse("Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36").Key()
+	expected := Key{Browser: BrowserChrome, BrowserMajor: 120, OS: OSAndroid, OSMajor: 14, DeviceModel: "pixel 8", DeviceType: DeviceTypeMobile}
+
+	if key != expected {
+		t.Errorf("expected %+v, but got %+v", expected, key)
+	}
+
+	// Chrome sends "K" instead of the model since the user agent reduction
+	key = Parse("Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36").Key()
+	if key.DeviceModel != "" {
+		t.Errorf("expected no device model, but got %q", key.DeviceModel)
+	}
+
+	counts := map[Key]int{}
+	for range 3 {
+		counts[Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)").Key()]++
+	}
+
+	if len(counts) != 1 {
+		t.Errorf("expected one key, but got %v", counts)
+	}
+}
+
+func TestKeyHashStable(t *testing.T) {
+	// These values must never change, stored hashes depend on them
+	testCases := []struct {
+		key  Key
+		hash uint64
+	}{
+		{Key{Browser: BrowserChrome, BrowserMajor: 120, OS: OSAndroid, OSMajor: 14, DeviceModel: "pixel 8", DeviceType: DeviceTypeMobile}, 15910598797476593858},
+		{Key{Browser: BrowserSafari, BrowserMajor: 17, OS: OSIOS, OSMajor: 17, DeviceModel: "iphone", DeviceType: DeviceTypeMobile}, 2769875768096131783},
+		{Key{Browser: BrowserChrome, BrowserMajor: 120, OS: OSWindows, OSMajor: 10, DeviceType: DeviceTypeDesktop}, 3753361036464071702},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		if got := tc.key.Hash(); got != tc.hash {
+			t.Errorf("expected %+v to hash to %d, but got %d", tc.key, tc.hash, got)
+		}
+	}
+
+	// Fields are separated, so moving text between them changes the hash
+	a := Key{Product: "ab", DeviceModel: "c"}
+	b := Key{Product: "a", DeviceModel: "bc"}
+
+	if a.Hash() == b.Hash() {
+		t.Errorf("expected %+v and %+v to hash differently", a, b)
+	}
+}
@@ -0,0 +1,675 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidExpression is wrapped by the SyntaxError returned by
+// CompileMatcher.
+var ErrInvalidExpression = errors.New("useragent: invalid expression")
+
+// SyntaxError describes an invalid matcher expression.
+type SyntaxError struct {
+	// Expr is the expression that failed to compile.
+	Expr string
+	// Offset is the byte offset in Expr where the error was found.
+	Offset int
+	// Msg describes the error.
+	Msg string
+}
+
+// Error returns the error message with the 1-based column of the error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s at column %d", ErrInvalidExpression, e.Msg, e.Offset+1)
+}
+
+// Unwrap returns ErrInvalidExpression.
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalidExpression
+}
+
+// Matcher is a compiled boolean expression over the fields of a parsed user
+// agent, such as
+//
+//	browser == "Safari" && os_version < 16 && device_type == "mobile"
+//
+// Expressions combine comparisons with &&, || and !, and parentheses.
+// Comparisons always have a field on the left and a literal on the right:
+//
+//   - string fields support ==, !=, in, not in, and =~ and !~ with a regular
+//     expression, e.g. user_agent =~ "(?i)electron/"
+//   - version fields support ==, !=, <, <=, > and >=, in and not in with
+//     versions such as 16 or "16.4.1"; comparisons with a version the user
+//     agent does not have are false, except != and not in
+//   - bool fields can be used on their own or compared with true and false
+//
+// Strings are written in double quotes with Go escapes, or in backquotes
+// without escapes, which is convenient for regular expressions. Sets are
+// written in brackets: os in ["ios", "android"].
+//
+// String fields are user_agent, browser, browser_family, os, device,
+// device_model, device_type, engine, bot_category, automation, class and
+// robots_token. Version fields are browser_version, os_version and
+// engine_version. Bool fields are is_bot, is_valid, is_mobile, is_tablet,
+// is_desktop, is_headless, is_anomalous, is_generic and has_threats. String
+// comparisons are case sensitive, and comparisons of enumerated fields such
+// as os and device_type are checked against the values the parser can
+// report.
+//
+// A Matcher is safe for concurrent use.
+type Matcher struct {
+	expr string
+	root matcherNode
+}
+
+// CompileMatcher compiles a matcher expression. Errors are *SyntaxError.
+func CompileMatcher(expr string) (*Matcher, error) {
+	tokens, err := lexMatcher(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &matcherParser{expr: expr, tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != matcherTokenEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+
+	return &Matcher{expr: expr, root: root}, nil
+}
+
+// MustCompileMatcher is like CompileMatcher but panics if the expression is
+// invalid.
+func MustCompileMatcher(expr string) *Matcher {
+	m, err := CompileMatcher(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Match returns true if the user agent matches the expression.
+func (m *Matcher) Match(ua *UserAgent) bool {
+	return m.root.match(ua)
+}
+
+// String returns the source of the expression.
+func (m *Matcher) String() string {
+	return m.expr
+}
+
+// matcherFieldKind is the type of a field.
+type matcherFieldKind int
+
+const (
+	matcherString matcherFieldKind = iota
+	matcherVersion
+	matcherBool
+)
+
+// matcherField is a field that can be used in expressions.
+type matcherField struct {
+	kind    matcherFieldKind
+	str     func(*UserAgent) string
+	version func(*UserAgent) Version
+	boolean func(*UserAgent) bool
+	// values are the values an enumerated string field can have
+	values []string
+}
+
+// matcherNode is a compiled part of an expression.
+type matcherNode interface {
+	match(ua *UserAgent) bool
+}
+
+type (
+	matcherOrNode    struct{ left, right matcherNode }
+	matcherAndNode   struct{ left, right matcherNode }
+	matcherNotNode   struct{ node matcherNode }
+	matcherConstNode bool
+	matcherBoolNode  struct{ get func(*UserAgent) bool }
+	// matcherStringNode matches a string field in a set of values
+	matcherStringNode struct {
+		get    func(*UserAgent) string
+		values []string
+		negate bool
+	}
+	matcherRegexNode struct {
+		get    func(*UserAgent) string
+		regex  *regexp.Regexp
+		negate bool
+	}
+	matcherVersionNode struct {
+		get    func(*UserAgent) Version
+		op     string
+		values []Version
+	}
+)
+
+func (n matcherOrNode) match(ua *UserAgent) bool {
+	return n.left.match(ua) || n.right.match(ua)
+}
+
+func (n matcherAndNode) match(ua *UserAgent) bool {
+	return n.left.match(ua) && n.right.match(ua)
+}
+
+func (n matcherNotNode) match(ua *UserAgent) bool {
+	return !n.node.match(ua)
+}
+
+func (n matcherConstNode) match(*UserAgent) bool {
+	return bool(n)
+}
+
+func (n matcherBoolNode) match(ua *UserAgent) bool {
+	return n.get(ua)
+}
+
+func (n matcherStringNode) match(ua *UserAgent) bool {
+	return slices.Contains(n.values, n.get(ua)) != n.negate
+}
+
+func (n matcherRegexNode) match(ua *UserAgent) bool {
+	return n.regex.MatchString(n.get(ua)) != n.negate
+}
+
+func (n matcherVersionNode) match(ua *UserAgent) bool {
+	v := n.get(ua)
+	if v.IsZero() {
+		return n.op == "!=" || n.op == "not in"
+	}
+
+	switch n.op {
+	case "==", "in":
+		return slices.ContainsFunc(n.values, func(other Version) bool { return v.Compare(other) == 0 })
+	case "!=", "not in":
+		return !slices.ContainsFunc(n.values, func(other Version) bool { return v.Compare(other) == 0 })
+	case "<":
+		return v.Compare(n.values[0]) < 0
+	case "<=":
+		return v.Compare(n.values[0]) <= 0
+	case ">":
+		return v.Compare(n.values[0]) > 0
+	case ">=":
+		return v.Compare(n.values[0]) >= 0
+	}
+
+	return false
+}
+
+// matcherTokenKind is the kind of a lexical token.
+type matcherTokenKind int
+
+const (
+	matcherTokenEOF matcherTokenKind = iota
+	matcherTokenIdent
+	matcherTokenString
+	matcherTokenNumber
+	matcherTokenOperator
+)
+
+// matcherToken is a lexical token of an expression.
+type matcherToken struct {
+	kind matcherTokenKind
+	// text is the token as written; value is the unquoted string
+	text  string
+	value string
+	pos   int
+}
+
+// String describes the token for error messages.
+func (t matcherToken) String() string {
+	if t.kind == matcherTokenEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// lexMatcher splits an expression into tokens.
+func lexMatcher(expr string) ([]matcherToken, error) {
+	var tokens []matcherToken
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(expr) {
+				return nil, &SyntaxError{Expr: expr, Offset: i, Msg: "unterminated string"}
+			}
+
+			value, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, &SyntaxError{Expr: expr, Offset: i, Msg: "invalid string " + expr[i:end+1]}
+			}
+
+			tokens = append(tokens, matcherToken{kind: matcherTokenString, text: expr[i : end+1], value: value, pos: i})
+			i = end + 1
+		case c == '`':
+			end := strings.IndexByte(expr[i+1:], '`')
+			if end < 0 {
+				return nil, &SyntaxError{Expr: expr, Offset: i, Msg: "unterminated string"}
+			}
+
+			text := expr[i : i+end+2]
+			tokens = append(tokens, matcherToken{kind: matcherTokenString, text: text, value: text[1 : len(text)-1], pos: i})
+			i += end + 2
+		case isMatcherIdentByte(c, false):
+			end := i
+			for end < len(expr) && isMatcherIdentByte(expr[end], true) {
+				end++
+			}
+
+			tokens = append(tokens, matcherToken{kind: matcherTokenIdent, text: expr[i:end], pos: i})
+			i = end
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(expr) && (expr[end] == '.' || (expr[end] >= '0' && expr[end] <= '9')) {
+				end++
+			}
+
+			tokens = append(tokens, matcherToken{kind: matcherTokenNumber, text: expr[i:end], pos: i})
+			i = end
+		default:
+			op := ""
+
+			for _, candidate := range matcherOperators {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+
+					break
+				}
+			}
+
+			if op == "" {
+				return nil, &SyntaxError{Expr: expr, Offset: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+
+			tokens = append(tokens, matcherToken{kind: matcherTokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, matcherToken{kind: matcherTokenEOF, pos: len(expr)}), nil
+}
+
+// isMatcherIdentByte returns true for bytes that can start an identifier, or
+// continue one if rest is true.
+func isMatcherIdentByte(c byte, rest bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (rest && c >= '0' && c <= '9')
+}
+
+// matcherParser is a recursive descent parser for expressions.
+type matcherParser struct {
+	expr   string
+	tokens []matcherToken
+	i      int
+}
+
+func (p *matcherParser) peek() matcherToken {
+	return p.tokens[p.i]
+}
+
+func (p *matcherParser) next() matcherToken {
+	tok := p.tokens[p.i]
+	if tok.kind != matcherTokenEOF {
+		p.i++
+	}
+
+	return tok
+}
+
+// accept consumes the next token if it is the operator or keyword text.
+func (p *matcherParser) accept(text string) bool {
+	tok := p.peek()
+	if (tok.kind == matcherTokenOperator || tok.kind == matcherTokenIdent) && tok.text == text {
+		p.i++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *matcherParser) errorf(pos int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Expr: p.expr, Offset: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses a || b || ...
+func (p *matcherParser) parseOr() (matcherNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = matcherOrNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses a && b && ...
+func (p *matcherParser) parseAnd() (matcherNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = matcherAndNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses !a.
+func (p *matcherParser) parseUnary() (matcherNode, error) {
+	if p.accept("!") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return matcherNotNode{node: node}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression, a constant, a bool field
+// or a comparison.
+func (p *matcherParser) parsePrimary() (matcherNode, error) {
+	tok := p.next()
+
+	if tok.kind == matcherTokenOperator && tok.text == "(" {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.text != ")" || closing.kind != matcherTokenOperator {
+			return nil, p.errorf(closing.pos, "expected \")\" but got %s", closing)
+		}
+
+		return node, nil
+	}
+
+	if tok.kind != matcherTokenIdent {
+		return nil, p.errorf(tok.pos, "expected a field but got %s", tok)
+	}
+
+	if tok.text == "true" || tok.text == "false" {
+		return matcherConstNode(tok.text == "true"), nil
+	}
+
+	field, ok := matcherFields[tok.text]
+	if !ok {
+		return nil, p.errorf(tok.pos, "unknown field %q", tok.text)
+	}
+
+	opTok := p.peek()
+
+	op, ok := p.parseOperator()
+	if !ok {
+		if field.kind == matcherBool {
+			return matcherBoolNode{get: field.boolean}, nil
+		}
+
+		return nil, p.errorf(opTok.pos, "expected a comparison after %s but got %s", tok.text, opTok)
+	}
+
+	values, err := p.parseValues(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.compileComparison(tok.text, field, op, opTok.pos, values)
+}
+
+// parseOperator consumes a comparison operator.
+func (p *matcherParser) parseOperator() (string, bool) {
+	tok := p.peek()
+
+	switch {
+	case tok.kind == matcherTokenOperator && slices.Contains(matcherComparisons[:], tok.text):
+		p.i++
+
+		return tok.text, true
+	case tok.kind == matcherTokenIdent && tok.text == "in":
+		p.i++
+
+		return "in", true
+	case tok.kind == matcherTokenIdent && tok.text == "not" && p.tokens[p.i+1].text == "in":
+		p.i += 2
+
+		return "not in", true
+	}
+
+	return "", false
+}
+
+// parseValues parses the literal, or bracketed list of literals for in and
+// not in, on the right of a comparison.
+func (p *matcherParser) parseValues(op string) ([]matcherToken, error) {
+	if op != "in" && op != "not in" {
+		tok, err := p.parseLiteral()
+
+		return []matcherToken{tok}, err
+	}
+
+	if tok := p.next(); tok.kind != matcherTokenOperator || tok.text != "[" {
+		return nil, p.errorf(tok.pos, "expected \"[\" after %s but got %s", op, tok)
+	}
+
+	var values []matcherToken
+
+	for !p.accept("]") {
+		if len(values) > 0 && !p.accept(",") {
+			tok := p.peek()
+
+			return nil, p.errorf(tok.pos, "expected \",\" or \"]\" but got %s", tok)
+		}
+
+		tok, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, tok)
+	}
+
+	if len(values) == 0 {
+		return nil, p.errorf(p.tokens[p.i-1].pos, "empty set")
+	}
+
+	return values, nil
+}
+
+// parseLiteral parses a string, number or boolean literal.
+func (p *matcherParser) parseLiteral() (matcherToken, error) {
+	tok := p.next()
+	if tok.kind == matcherTokenString || tok.kind == matcherTokenNumber ||
+		(tok.kind == matcherTokenIdent && (tok.text == "true" || tok.text == "false")) {
+		return tok, nil
+	}
+
+	return tok, p.errorf(tok.pos, "expected a value but got %s", tok)
+}
+
+// compileComparison type checks a comparison and returns its node.
+func (p *matcherParser) compileComparison(name string, field matcherField, op string, opPos int,
+	values []matcherToken,
+) (matcherNode, error) {
+	switch field.kind {
+	case matcherBool:
+		return p.compileBoolComparison(name, field, op, opPos, values)
+	case matcherVersion:
+		return p.compileVersionComparison(name, field, op, opPos, values)
+	case matcherString:
+		return p.compileStringComparison(name, field, op, opPos, values)
+	}
+
+	return nil, p.errorf(opPos, "operator %s is not supported for %s", op, name)
+}
+
+// compileBoolComparison compiles a comparison of a bool field.
+func (p *matcherParser) compileBoolComparison(name string, field matcherField, op string, opPos int,
+	values []matcherToken,
+) (matcherNode, error) {
+	if op != "==" && op != "!=" {
+		return nil, p.errorf(opPos, "operator %s is not supported for %s", op, name)
+	}
+
+	if values[0].kind != matcherTokenIdent {
+		return nil, p.errorf(values[0].pos, "%s is a bool field and can only be compared with true or false", name)
+	}
+
+	if (values[0].text == "true") == (op == "==") {
+		return matcherBoolNode{get: field.boolean}, nil
+	}
+
+	return matcherNotNode{node: matcherBoolNode{get: field.boolean}}, nil
+}
+
+// compileVersionComparison compiles a comparison of a version field.
+func (p *matcherParser) compileVersionComparison(name string, field matcherField, op string, opPos int,
+	values []matcherToken,
+) (matcherNode, error) {
+	if op == "=~" || op == "!~" {
+		return nil, p.errorf(opPos, "operator %s is not supported for %s", op, name)
+	}
+
+	versions := make([]Version, 0, len(values))
+
+	for _, tok := range values {
+		text := tok.text
+		if tok.kind == matcherTokenString {
+			text = tok.value
+		}
+
+		v, err := ParseVersion(text)
+		if err != nil || tok.kind == matcherTokenIdent {
+			return nil, p.errorf(tok.pos, "%s is not a valid version", tok)
+		}
+
+		versions = append(versions, v)
+	}
+
+	return matcherVersionNode{get: field.version, op: op, values: versions}, nil
+}
+
+// compileStringComparison compiles a comparison of a string field.
+func (p *matcherParser) compileStringComparison(name string, field matcherField, op string, opPos int,
+	values []matcherToken,
+) (matcherNode, error) {
+	strs := make([]string, 0, len(values))
+
+	for _, tok := range values {
+		if tok.kind != matcherTokenString {
+			return nil, p.errorf(tok.pos, "%s is a string field and must be compared with a quoted string", name)
+		}
+
+		strs = append(strs, tok.value)
+	}
+
+	switch op {
+	case "=~", "!~":
+		re, err := regexp.Compile(strs[0])
+		if err != nil {
+			return nil, p.errorf(values[0].pos, "invalid regular expression: %v", err)
+		}
+
+		return matcherRegexNode{get: field.str, regex: re, negate: op == "!~"}, nil
+	case "==", "!=", "in", "not in":
+		for i, s := range strs {
+			if field.values != nil && !slices.Contains(field.values, s) {
+				return nil, p.errorf(values[i].pos, "%s can never be %q, expected one of %s", name, s,
+					strings.Join(field.values, ", "))
+			}
+		}
+
+		return matcherStringNode{get: field.str, values: strs, negate: op == "!=" || op == "not in"}, nil
+	}
+
+	return nil, p.errorf(opPos, "operator %s is not supported for %s", op, name)
+}
+
+// enumStrings returns the values of an enumeration as strings.
+func enumStrings[T ~string](values []T) []string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+
+	return strs
+}
+
+var (
+	// matcherOperators are the operators of the language, longest first so
+	// "<=" is not lexed as "<".
+	matcherOperators = [...]string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")", "[", "]", ","}
+
+	matcherComparisons = [...]string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">"}
+
+	matcherFields = map[string]matcherField{
+		"user_agent":      {kind: matcherString, str: (*UserAgent).UserAgent},
+		"browser":         {kind: matcherString, str: (*UserAgent).Browser},
+		"browser_family":  {kind: matcherString, str: func(ua *UserAgent) string { return ua.browserFamily.String() }, values: enumStrings(AllBrowserFamilies())},
+		"os":              {kind: matcherString, str: (*UserAgent).OperatingSystem, values: enumStrings(AllOSFamilies())},
+		"device":          {kind: matcherString, str: (*UserAgent).Device},
+		"device_model":    {kind: matcherString, str: (*UserAgent).DeviceModel},
+		"device_type":     {kind: matcherString, str: (*UserAgent).DeviceType, values: enumStrings(AllDeviceTypes())},
+		"engine":          {kind: matcherString, str: func(ua *UserAgent) string { return ua.engine.String() }, values: enumStrings(AllEngines())},
+		"bot_category":    {kind: matcherString, str: func(ua *UserAgent) string { return ua.botCategory.String() }, values: enumStrings(AllBotCategories())},
+		"automation":      {kind: matcherString, str: (*UserAgent).Automation},
+		"class":           {kind: matcherString, str: func(ua *UserAgent) string { return ua.Classify().Class.String() }, values: enumStrings(AllClasses())},
+		"robots_token":    {kind: matcherString, str: (*UserAgent).RobotsToken},
+		"browser_version": {kind: matcherVersion, version: (*UserAgent).BrowserVersion},
+		"os_version":      {kind: matcherVersion, version: (*UserAgent).OSVersion},
+		"engine_version":  {kind: matcherVersion, version: (*UserAgent).EngineVersion},
+		"is_bot":          {kind: matcherBool, boolean: func(ua *UserAgent) bool { return ua.IsBot(true) }},
+		"is_valid":        {kind: matcherBool, boolean: (*UserAgent).IsValid},
+		"is_mobile":       {kind: matcherBool, boolean: (*UserAgent).IsMobile},
+		"is_tablet":       {kind: matcherBool, boolean: (*UserAgent).IsTablet},
+		"is_desktop":      {kind: matcherBool, boolean: (*UserAgent).IsDesktop},
+		"is_headless":     {kind: matcherBool, boolean: (*UserAgent).IsHeadless},
+		"is_anomalous":    {kind: matcherBool, boolean: (*UserAgent).IsAnomalous},
+		"is_generic":      {kind: matcherBool, boolean: (*UserAgent).IsGeneric},
+		"has_threats":     {kind: matcherBool, boolean: (*UserAgent).HasThreats},
+	}
+)
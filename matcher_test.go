@@ -0,0 +1,112 @@
+package useragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	const (
+		safariIPhone15 = "Mozilla/5.0 (iPhone; CPU iPhone OS 15_8 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.6.6 Mobile/15E148 Safari/604.1"
+		safariIPhone17 = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"
+		chromeWindows  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		electron       = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.36.140 Chrome/120.0.6099.56 Electron/28.0.0 Safari/537.36"
+		googlebot      = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+		headless       = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36"
+	)
+
+	testCases := []struct {
+		name      string
+		expr      string
+		userAgent string
+		match     bool
+	}{
+		{name: "Example matches", expr: `browser == "Safari" && os_version < 16 && device_type == "mobile"`, userAgent: safariIPhone15, match: true},
+		{name: "Example newer iOS", expr: `browser == "Safari" && os_version < 16 && device_type == "mobile"`, userAgent: safariIPhone17, match: false},
+		{name: "Version compared numerically", expr: `browser_version >= 9`, userAgent: chromeWindows, match: true},
+		{name: "Dotted version", expr: `os_version == "15.8"`, userAgent: safariIPhone15, match: true},
+		{name: "Version set", expr: `browser_version in [119, 120]`, userAgent: chromeWindows, match: true},
+		{name: "Missing version is not less", expr: `os_version < 100`, userAgent: googlebot, match: false},
+		{name: "Missing version is not equal", expr: `os_version != 100`, userAgent: googlebot, match: true},
+		{name: "Set membership", expr: `os in ["ios", "android"]`, userAgent: safariIPhone17, match: true},
+		{name: "Not in set", expr: `os not in ["ios", "android"]`, userAgent: safariIPhone17, match: false},
+		{name: "Regex", expr: `user_agent =~ "(?i)electron/"`, userAgent: electron, match: true},
+		{name: "Raw string regex", expr: "user_agent =~ `Electron/\\d+`", userAgent: electron, match: true},
+		{name: "Negated regex", expr: `user_agent !~ "(?i)electron/"`, userAgent: electron, match: false},
+		{name: "Bool field", expr: `is_bot`, userAgent: googlebot, match: true},
+		{name: "Bool comparison", expr: `is_bot == false`, userAgent: googlebot, match: false},
+		{name: "Not", expr: `!is_bot && is_desktop`, userAgent: chromeWindows, match: true},
+		{name: "Precedence", expr: `is_bot || is_mobile && is_desktop`, userAgent: googlebot, match: true},
+		{name: "Parentheses", expr: `(is_bot || is_mobile) && is_desktop`, userAgent: safariIPhone17, match: false},
+		{name: "Bot category", expr: `bot_category == "search_engine" && robots_token == "Googlebot"`, userAgent: googlebot, match: true},
+		{name: "Automation", expr: `is_headless && automation == "HeadlessChrome"`, userAgent: headless, match: true},
+		{name: "Engine", expr: `engine == "Blink" && engine_version >= 120`, userAgent: chromeWindows, match: true},
+		{name: "Generic", expr: `is_generic && browser == "MyCompanyApp" && browser_version >= 4`, userAgent: "MyCompanyApp/4.2.1 (iPhone; iOS 17.1)", match: true},
+		{name: "Class", expr: `class == "unknown"`, userAgent: "MyCompanyApp/4.2.1 (iPhone; iOS 17.1)", match: true},
+		{name: "Threats", expr: `has_threats && class == "bot"`, userAgent: "() { :; }; /bin/bash -c id", match: true},
+		{name: "Constant", expr: `true && !false`, userAgent: chromeWindows, match: true},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := CompileMatcher(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if m.Match(Parse(tc.userAgent)) != tc.match {
+				t.Errorf("expected %s to be %t", tc.expr, tc.match)
+			}
+		})
+	}
+}
+
+func TestCompileMatcherErrors(t *testing.T) {
+	testCases := []struct {
+		expr   string
+		offset int
+	}{
+		{expr: ``, offset: 0},
+		{expr: `browser ==`, offset: 10},
+		{expr: `browser == "Safari`, offset: 11},
+		{expr: `browsr == "Safari"`, offset: 0},
+		{expr: `browser < "Safari"`, offset: 8},
+		{expr: `browser == Safari`, offset: 11},
+		{expr: `os == "macOS"`, offset: 6},
+		{expr: `os_version < "sixteen"`, offset: 13},
+		{expr: `os_version < 16.`, offset: 13},
+		{expr: `is_bot == "yes"`, offset: 10},
+		{expr: `device_type`, offset: 11},
+		{expr: `(is_bot`, offset: 7},
+		{expr: `is_bot is_mobile`, offset: 7},
+		{expr: `user_agent =~ "("`, offset: 14},
+		{expr: `os in []`, offset: 7},
+		{expr: `os in ["ios" "android"]`, offset: 13},
+		{expr: `is_bot & is_mobile`, offset: 7},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		_, err := CompileMatcher(tc.expr)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("expected a SyntaxError for %q, but got %v", tc.expr, err)
+
+			continue
+		}
+
+		if !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("expected %q to wrap ErrInvalidExpression", tc.expr)
+		}
+
+		if syntaxErr.Offset != tc.offset {
+			t.Errorf("expected error in %q at offset %d, but got %d: %v", tc.expr, tc.offset, syntaxErr.Offset, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package useragent
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// defaultUpgradePage is served to unsupported browsers when no upgrade page
+// or redirect is configured.
+const defaultUpgradePage = `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Unsupported browser</title></head>
+<body><p>This browser is not supported. Please upgrade to a recent version.</p></body>
+</html>
+`
+
+// BrowserSupport is a policy of supported browsers that can be enforced with
+// Middleware.
+type BrowserSupport struct {
+	// MinVersions is the minimum version of each browser family, e.g.
+	// MustParseVersion("109") for Chrome. Families that are not listed, and
+	// browsers whose version is unknown, are supported.
+	MinVersions map[BrowserFamily]Version
+	// Browserslist, if set, must support the browser as well.
+	Browserslist *Browserslist
+	// AllowBots lets bots, crawlers and automation tools through.
+	AllowBots bool
+	// OverrideCookie is the name of a cookie that lets any browser through
+	// when it is set, e.g. after a "continue anyway" link.
+	OverrideCookie string
+	// RedirectURL redirects unsupported browsers instead of serving the
+	// upgrade page. Requests for the path of RedirectURL are let through,
+	// whatever their query.
+	RedirectURL string
+	// UpgradePage serves unsupported browsers. A simple page saying the
+	// browser is not supported is served when it is nil.
+	UpgradePage http.Handler
+}
+
+// Supports returns true if the policy supports the browser of the user
+// agent. Unknown clients are not bots, they are checked like browsers.
+func (s *BrowserSupport) Supports(ua *UserAgent) bool {
+	if ua.BotCategory() != BotNone {
+		return s.AllowBots
+	}
+
+	if minVersion, ok := s.MinVersions[ua.browserFamily]; ok && !ua.browserVersion.IsZero() &&
+		!ua.browserVersion.AtLeast(minVersion) {
+		return false
+	}
+
+	return s.Browserslist == nil || s.Browserslist.Supports(ua)
+}
+
+// Middleware returns a handler that calls next for supported browsers and
+// serves the upgrade page, or redirects, for unsupported ones.
+func (s *BrowserSupport) Middleware(next http.Handler) http.Handler {
+	var redirect *url.URL
+	if s.RedirectURL != "" {
+		redirect, _ = url.Parse(s.RedirectURL)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "User-Agent")
+		if s.OverrideCookie != "" {
+			w.Header().Add("Vary", "Cookie")
+		}
+
+		if s.allowed(r, redirect) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if s.RedirectURL != "" {
+			http.Redirect(w, r, s.RedirectURL, http.StatusFound)
+
+			return
+		}
+
+		if s.UpgradePage != nil {
+			s.UpgradePage.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(defaultUpgradePage))
+	})
+}
+
+// allowed returns true if the request may be passed to the next handler.
+// Requests for the redirect target are always allowed, so the redirect does
+// not loop.
+func (s *BrowserSupport) allowed(r *http.Request, redirect *url.URL) bool {
+	if redirect != nil && (redirect.Host == "" || redirect.Host == r.Host) && r.URL.Path == redirect.Path {
+		return true
+	}
+
+	if s.OverrideCookie != "" {
+		if cookie, err := r.Cookie(s.OverrideCookie); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+
+	return s.Supports(Parse(r.UserAgent()))
+}
@@ -0,0 +1,215 @@
+package useragent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBrowserSupportMiddleware(t *testing.T) {
+	const (
+		chrome141 = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36"
+		chrome109 = "Mozilla/5.0 (Windows NT 6.1; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36"
+		chrome9   = "Mozilla/5.0 (Windows NT 6.1) AppleWebKit/534.13 (KHTML, like Gecko) Chrome/9.0.597.0 Safari/534.13"
+		ie11      = "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko"
+		vivaldi   = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/100.0.0.0 Safari/537.36 Vivaldi/5.2.2623.41"
+		googlebot = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("next"))
+	})
+
+	minVersions := map[BrowserFamily]Version{
+		BrowserChrome:           MustParseVersion("110"),
+		BrowserInternetExplorer: MustParseVersion("12"),
+	}
+
+	testCases := []struct {
+		name      string
+		support   BrowserSupport
+		userAgent string
+		path      string
+		cookie    *http.Cookie
+		status    int
+		body      string
+		location  string
+	}{
+		{
+			name:      "Supported browser",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: chrome141,
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Old browser gets default upgrade page",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: chrome109,
+			status:    http.StatusOK,
+			body:      "not supported",
+		},
+		{
+			name:      "Versions are compared numerically",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: chrome9,
+			status:    http.StatusOK,
+			body:      "not supported",
+		},
+		{
+			name:      "Unlisted family",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: vivaldi,
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name: "Custom upgrade page",
+			support: BrowserSupport{
+				MinVersions: minVersions,
+				UpgradePage: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusUpgradeRequired)
+					_, _ = w.Write([]byte("upgrade"))
+				}),
+			},
+			userAgent: ie11,
+			status:    http.StatusUpgradeRequired,
+			body:      "upgrade",
+		},
+		{
+			name:      "Redirect",
+			support:   BrowserSupport{MinVersions: minVersions, RedirectURL: "/upgrade"},
+			userAgent: chrome109,
+			path:      "/app",
+			status:    http.StatusFound,
+			location:  "/upgrade",
+		},
+		{
+			name:      "Redirect target is let through",
+			support:   BrowserSupport{MinVersions: minVersions, RedirectURL: "/upgrade"},
+			userAgent: chrome109,
+			path:      "/upgrade",
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Redirect target with a query is let through",
+			support:   BrowserSupport{MinVersions: minVersions, RedirectURL: "/upgrade?from=app"},
+			userAgent: chrome109,
+			path:      "/upgrade?from=app",
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Redirect to another host",
+			support:   BrowserSupport{MinVersions: minVersions, RedirectURL: "https://upgrade.example.com/upgrade"},
+			userAgent: chrome109,
+			path:      "/upgrade",
+			status:    http.StatusFound,
+			location:  "https://upgrade.example.com/upgrade",
+		},
+		{
+			name:      "Unknown client is not a bot",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: "MyApp/1.0",
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Empty user agent is not a bot",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: "",
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Bots blocked by default",
+			support:   BrowserSupport{MinVersions: minVersions},
+			userAgent: googlebot,
+			status:    http.StatusOK,
+			body:      "not supported",
+		},
+		{
+			name:      "Bots allowed",
+			support:   BrowserSupport{MinVersions: minVersions, AllowBots: true},
+			userAgent: googlebot,
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Override cookie",
+			support:   BrowserSupport{MinVersions: minVersions, OverrideCookie: "unsupported_ok"},
+			userAgent: ie11,
+			cookie:    &http.Cookie{Name: "unsupported_ok", Value: "1"},
+			status:    http.StatusOK,
+			body:      "next",
+		},
+		{
+			name:      "Other cookie",
+			support:   BrowserSupport{MinVersions: minVersions, OverrideCookie: "unsupported_ok"},
+			userAgent: ie11,
+			cookie:    &http.Cookie{Name: "session", Value: "1"},
+			status:    http.StatusOK,
+			body:      "not supported",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := tc.path
+			if path == "" {
+				path = "/"
+			}
+
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.Header.Set("User-Agent", tc.userAgent)
+
+			if tc.cookie != nil {
+				r.AddCookie(tc.cookie)
+			}
+
+			w := httptest.NewRecorder()
+			tc.support.Middleware(next).ServeHTTP(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("expected status %d, but got %d", tc.status, w.Code)
+			}
+
+			if !strings.Contains(w.Body.String(), tc.body) {
+				t.Errorf("expected body to contain %q, but got %q", tc.body, w.Body.String())
+			}
+
+			if location := w.Header().Get("Location"); location != tc.location {
+				t.Errorf("expected location %q, but got %q", tc.location, location)
+			}
+
+			if w.Header().Get("Vary") != "User-Agent" {
+				t.Errorf("expected Vary: User-Agent, but got %q", w.Header().Values("Vary"))
+			}
+		})
+	}
+}
+
+func TestBrowserSupportBrowserslist(t *testing.T) {
+	t.Parallel()
+
+	browserslist, err := ParseBrowserslist("last 2 Chrome versions")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	support := &BrowserSupport{Browserslist: browserslist}
+
+	if !support.Supports(Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36")) {
+		t.Error("expected the latest Chrome to be supported")
+	}
+
+	if support.Supports(Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:144.0) Gecko/20100101 Firefox/144.0")) {
+		t.Error("expected Firefox not to be supported")
+	}
+}
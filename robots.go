@@ -0,0 +1,320 @@
+package useragent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRobotsSize is the number of bytes of a robots.txt file that are parsed,
+// the minimum RFC 9309 requires crawlers to process.
+const maxRobotsSize = 500 * 1024
+
+// Robots is a parsed robots.txt file as specified by RFC 9309.
+type Robots struct {
+	groups   []robotsGroup
+	sitemaps []string
+}
+
+// RobotsGroup holds the robots.txt rules that apply to a single crawler.
+type RobotsGroup struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	hasDelay   bool
+}
+
+// robotsGroup is a group of a robots.txt file with the user agents it
+// applies to.
+type robotsGroup struct {
+	agents []string
+	group  RobotsGroup
+}
+
+// robotsRule is a single allow or disallow rule.
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// ParseRobots parses the contents of a robots.txt file. Parsing is lenient as
+// RFC 9309 requires: unknown lines are ignored and only the first 500 KiB are
+// read.
+func ParseRobots(data []byte) *Robots {
+	if len(data) > maxRobotsSize {
+		data = data[:maxRobotsSize]
+	}
+
+	robots := &Robots{}
+
+	var current *robotsGroup
+
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), maxRobotsSize)
+
+	for scanner.Scan() {
+		key, value, ok := parseRobotsLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				robots.groups = append(robots.groups, robotsGroup{})
+				current = &robots.groups[len(robots.groups)-1]
+			}
+
+			current.agents = append(current.agents, robotsAgent(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+
+			// Rules outside of a group and empty rules are ignored
+			if current == nil || value == "" {
+				continue
+			}
+
+			current.group.rules = append(current.group.rules, robotsRule{allow: key == "allow", pattern: normalizeRobotsPath(value)})
+		case "crawl-delay":
+			inAgents = false
+
+			seconds, err := strconv.ParseFloat(value, 64)
+			if current == nil || err != nil || seconds < 0 {
+				continue
+			}
+
+			current.group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			current.group.hasDelay = true
+		case "sitemap":
+			robots.sitemaps = append(robots.sitemaps, value)
+		}
+	}
+
+	return robots
+}
+
+// Sitemaps returns the sitemap URLs listed in the robots.txt file.
+func (r *Robots) Sitemaps() []string {
+	return r.sitemaps
+}
+
+// Group returns the rules that apply to the user agent. The groups naming
+// its robots.txt product token are combined; if there are none, the groups
+// for "*" are used. Crawler variants such as Googlebot-Image fall back to the
+// groups of their parent crawler first. User agents without a product token,
+// such as browsers, get the "*" groups.
+func (r *Robots) Group(ua *UserAgent) *RobotsGroup {
+	for _, token := range ua.robotsTokens() {
+		if group, ok := r.group(token); ok {
+			return group
+		}
+	}
+
+	group, _ := r.group("*")
+
+	return group
+}
+
+// Allowed returns true if the user agent may crawl path.
+func (r *Robots) Allowed(ua *UserAgent, path string) bool {
+	return r.Group(ua).Allowed(path)
+}
+
+// group returns the combined groups for a product token.
+func (r *Robots) group(token string) (*RobotsGroup, bool) {
+	combined := &RobotsGroup{}
+	found := false
+
+	for i := range r.groups {
+		g := &r.groups[i]
+		if !g.matches(token) {
+			continue
+		}
+
+		found = true
+
+		combined.rules = append(combined.rules, g.group.rules...)
+		if g.group.hasDelay && !combined.hasDelay {
+			combined.crawlDelay = g.group.crawlDelay
+			combined.hasDelay = true
+		}
+	}
+
+	return combined, found
+}
+
+// matches returns true if the group applies to the product token.
+func (g *robotsGroup) matches(token string) bool {
+	for _, agent := range g.agents {
+		if strings.EqualFold(agent, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allowed returns true if path may be crawled. The most specific matching
+// rule wins, and allow wins over disallow when both are equally specific.
+// The path may include a query string; /robots.txt is always allowed.
+func (g *RobotsGroup) Allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+
+	if path == "/robots.txt" {
+		return true
+	}
+
+	path = normalizeRobotsPath(path)
+
+	allowed := true
+	longest := -1
+
+	for _, rule := range g.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed = rule.allow
+			longest = len(rule.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// CrawlDelay returns the crawl delay of the group and whether one was set.
+func (g *RobotsGroup) CrawlDelay() (time.Duration, bool) {
+	return g.crawlDelay, g.hasDelay
+}
+
+// RobotsToken returns the robots.txt product token of a bot, such as
+// "Googlebot" or "GPTBot". Crawler variants get their own token, such as
+// "Googlebot-Image". For bots without a known token the crawler name in the
+// user agent is used. Browsers have no product token and get an empty string.
+func (ua *UserAgent) RobotsToken() string {
+	if cp := ua.crawlerVariant(); cp != nil {
+		return cp.token
+	}
+
+	if ua.robotsToken != "" || ua.botCategory == BotNone {
+		return ua.robotsToken
+	}
+
+	return firstSubmatch(robotsTokenRegEx, ua.userAgent)
+}
+
+// robotsTokens returns the product tokens to look up in robots.txt, most
+// specific first.
+func (ua *UserAgent) robotsTokens() []string {
+	token := ua.RobotsToken()
+	if token == "" {
+		return nil
+	}
+
+	if cp := ua.crawlerVariant(); cp != nil && cp.parentToken != "" {
+		return []string{token, cp.parentToken}
+	}
+
+	return []string{token}
+}
+
+// parseRobotsLine returns the lower case key and the value of a robots.txt
+// line, without comments and surrounding whitespace.
+func parseRobotsLine(line string) (string, string, bool) {
+	line, _, _ = strings.Cut(line, "#")
+
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+// robotsAgent returns the product token of a user-agent line, which ends at
+// the first whitespace or slash.
+func robotsAgent(value string) string {
+	end := strings.IndexAny(value, " \t/")
+	if end >= 0 {
+		value = value[:end]
+	}
+
+	return value
+}
+
+// normalizeRobotsPath percent-encodes bytes outside of US-ASCII and uses upper
+// case hexadecimal digits for existing escapes, so paths and patterns can be
+// compared byte by byte.
+func normalizeRobotsPath(path string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+
+		switch {
+		case c >= 0x80 || c <= 0x20:
+			fmt.Fprintf(&b, "%%%02X", c)
+		case c == '%' && i+2 < len(path) && isHex(path[i+1]) && isHex(path[i+2]):
+			b.WriteString("%" + strings.ToUpper(path[i+1:i+3]))
+
+			i += 2
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// isHex returns true if c is a hexadecimal digit.
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// matchRobotsPattern returns true if pattern matches the start of path. A "*"
+// in the pattern matches any sequence of characters and a trailing "$"
+// anchors the pattern at the end of the path.
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	p, s := 0, 0
+	star, starS := -1, 0
+
+	for s < len(path) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, starS = p, s
+			p++
+		case p < len(pattern) && pattern[p] == path[s]:
+			p++
+			s++
+		case p == len(pattern) && !anchored:
+			return true
+		case star >= 0:
+			starS++
+			p, s = star+1, starS
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+var robotsTokenRegEx = regexp.MustCompile(`(?i)([a-z0-9_.-]*(?:bot|crawler|spider)[a-z0-9_.-]*)`)
@@ -0,0 +1,215 @@
+package useragent
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RobotsRule is the access a group of bots gets in a generated robots.txt.
+type RobotsRule struct {
+	// Deny disallows the whole site. Allow paths are still crawlable.
+	Deny bool
+	// Disallow lists the paths that may not be crawled when Deny is false.
+	Disallow []string
+	// Allow lists exceptions to Deny and Disallow.
+	Allow []string
+	// CrawlDelay is emitted as Crawl-delay when positive.
+	CrawlDelay time.Duration
+}
+
+// RobotsPolicy describes which bots may crawl which paths. The rule for a bot
+// is taken from Bots, then AIPurposes, then Categories, then Default.
+type RobotsPolicy struct {
+	// Default applies to every bot without a more specific rule, and is
+	// emitted for "*".
+	Default RobotsRule
+	// Categories holds the rules per bot category.
+	Categories map[BotCategory]RobotsRule
+	// AIPurposes holds the rules per AI agent purpose, e.g. to deny training
+	// crawlers but allow user triggered fetchers.
+	AIPurposes map[AIPurpose]RobotsRule
+	// Bots holds the rules per robots.txt product token, such as "GPTBot".
+	// Tokens are compared case-insensitively.
+	Bots map[string]RobotsRule
+	// Sitemaps lists sitemap URLs to include.
+	Sitemaps []string
+}
+
+// robotsPolicyGroup is a rule with the product tokens it applies to.
+type robotsPolicyGroup struct {
+	tokens []string
+	rule   RobotsRule
+}
+
+// Generate returns a robots.txt file for the policy, naming the product token
+// of every known bot whose rule differs from the default. Bots that share a
+// rule are listed in the same group.
+func (p *RobotsPolicy) Generate() string {
+	var groups []robotsPolicyGroup
+
+	bots, tokens := p.botRules()
+	seen := map[string]bool{}
+
+	for _, bot := range robotsPolicyBots() {
+		for _, token := range bot.Tokens {
+			key := strings.ToLower(token)
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			rule := p.rule(bots, token, bot.Category)
+			if rule.equal(p.Default) {
+				continue
+			}
+
+			groups = addRobotsPolicyGroup(groups, token, rule)
+		}
+	}
+
+	// Bots that are only named in the policy still get their rule
+	for _, token := range tokens {
+		rule := bots[strings.ToLower(token)]
+		if seen[strings.ToLower(token)] || rule.equal(p.Default) {
+			continue
+		}
+
+		groups = addRobotsPolicyGroup(groups, token, rule)
+	}
+
+	var b strings.Builder
+
+	for _, group := range groups {
+		writeRobotsGroup(&b, group.tokens, group.rule)
+		b.WriteString("\n")
+	}
+
+	writeRobotsGroup(&b, []string{"*"}, p.Default)
+
+	if len(p.Sitemaps) > 0 {
+		b.WriteString("\n")
+
+		for _, sitemap := range p.Sitemaps {
+			b.WriteString("Sitemap: " + sitemap + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// botRules returns the rules of Bots keyed by lowercase token, and the
+// tokens in sorted order. When tokens only differ in case, the first in
+// sorted order wins, so the result does not depend on map order.
+func (p *RobotsPolicy) botRules() (map[string]RobotsRule, []string) {
+	sorted := make([]string, 0, len(p.Bots))
+	for token := range p.Bots {
+		sorted = append(sorted, token)
+	}
+
+	slices.Sort(sorted)
+
+	rules := make(map[string]RobotsRule, len(sorted))
+	tokens := make([]string, 0, len(sorted))
+
+	for _, token := range sorted {
+		key := strings.ToLower(token)
+		if _, ok := rules[key]; ok {
+			continue
+		}
+
+		rules[key] = p.Bots[token]
+		tokens = append(tokens, token)
+	}
+
+	return rules, tokens
+}
+
+// rule returns the rule for a bot, looking the token up in the rules from
+// botRules.
+func (p *RobotsPolicy) rule(bots map[string]RobotsRule, token string, category BotCategory) RobotsRule {
+	if rule, ok := bots[strings.ToLower(token)]; ok {
+		return rule
+	}
+
+	for _, agent := range aiAgents {
+		if !strings.EqualFold(agent.RobotsToken, token) {
+			continue
+		}
+
+		if rule, ok := p.AIPurposes[agent.Purpose]; ok {
+			return rule
+		}
+	}
+
+	if rule, ok := p.Categories[category]; ok {
+		return rule
+	}
+
+	return p.Default
+}
+
+// robotsPolicyBots returns the known bots followed by the AI agents that only
+// exist in robots.txt.
+func robotsPolicyBots() []Bot {
+	bots := KnownBots()
+
+	for _, agent := range aiAgents {
+		if agent.RobotsOnly {
+			bots = append(bots, Bot{Name: agent.Name, Category: BotAI, Tokens: []string{agent.RobotsToken}})
+		}
+	}
+
+	return bots
+}
+
+// equal returns true if both rules produce the same robots.txt lines.
+func (r RobotsRule) equal(other RobotsRule) bool {
+	return r.Deny == other.Deny &&
+		slices.Equal(r.Disallow, other.Disallow) &&
+		slices.Equal(r.Allow, other.Allow) &&
+		r.CrawlDelay == other.CrawlDelay
+}
+
+// addRobotsPolicyGroup adds the token to the group with the same rule, or to a
+// new group if there is none.
+func addRobotsPolicyGroup(groups []robotsPolicyGroup, token string, rule RobotsRule) []robotsPolicyGroup {
+	for i := range groups {
+		if groups[i].rule.equal(rule) {
+			groups[i].tokens = append(groups[i].tokens, token)
+
+			return groups
+		}
+	}
+
+	return append(groups, robotsPolicyGroup{tokens: []string{token}, rule: rule})
+}
+
+// writeRobotsGroup writes a group for the tokens. Every group has at least
+// one rule, since consecutive user-agent lines would otherwise merge groups.
+func writeRobotsGroup(b *strings.Builder, tokens []string, rule RobotsRule) {
+	for _, token := range tokens {
+		b.WriteString("User-agent: " + token + "\n")
+	}
+
+	switch {
+	case rule.Deny:
+		b.WriteString("Disallow: /\n")
+	case len(rule.Disallow) == 0 && len(rule.Allow) == 0:
+		b.WriteString("Allow: /\n")
+	default:
+		for _, path := range rule.Disallow {
+			b.WriteString("Disallow: " + path + "\n")
+		}
+	}
+
+	for _, path := range rule.Allow {
+		b.WriteString("Allow: " + path + "\n")
+	}
+
+	if rule.CrawlDelay > 0 {
+		b.WriteString("Crawl-delay: " + strconv.FormatFloat(rule.CrawlDelay.Seconds(), 'f', -1, 64) + "\n")
+	}
+}
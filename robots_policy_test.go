@@ -0,0 +1,158 @@
+package useragent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRobotsPolicyGenerate(t *testing.T) {
+	t.Parallel()
+
+	policy := &RobotsPolicy{
+		Default: RobotsRule{Disallow: []string{"/admin"}},
+		Categories: map[BotCategory]RobotsRule{
+			BotAI:  {Deny: true},
+			BotSEO: {Deny: true, Allow: []string{"/blog"}},
+		},
+		Bots: map[string]RobotsRule{
+			"bingbot":  {Disallow: []string{"/admin"}, CrawlDelay: 1500 * time.Millisecond},
+			"MyCustom": {Deny: true},
+		},
+		Sitemaps: []string{"https://example.com/sitemap.xml"},
+	}
+
+	robotsTxt := policy.Generate()
+
+	for _, expected := range []string{
+		"User-agent: GPTBot\n",
+		"User-agent: ClaudeBot\n",
+		"User-agent: ChatGPT-User\n",
+		"User-agent: AhrefsBot\nUser-agent: SemrushBot\nUser-agent: rogerbot\nUser-agent: dotbot\nDisallow: /\nAllow: /blog\n",
+		"User-agent: bingbot\nDisallow: /admin\nCrawl-delay: 1.5\n",
+		"User-agent: MyCustom\nDisallow: /\n",
+		"User-agent: *\nDisallow: /admin\n",
+		"Sitemap: https://example.com/sitemap.xml\n",
+	} {
+		if !strings.Contains(robotsTxt, expected) {
+			t.Errorf("expected robots.txt to contain %q, but got:\n%s", expected, robotsTxt)
+		}
+	}
+
+	if strings.Contains(robotsTxt, "User-agent: Googlebot\n") {
+		t.Errorf("expected Googlebot to be covered by the default group, but got:\n%s", robotsTxt)
+	}
+
+	robots := ParseRobots([]byte(robotsTxt))
+	bots, _ := policy.botRules()
+
+	for _, bot := range KnownBots() {
+		for _, token := range bot.Tokens {
+			group, ok := robots.group(token)
+			if !ok {
+				group, _ = robots.group("*")
+			}
+
+			rule := policy.rule(bots, token, bot.Category)
+			if group.Allowed("/") == rule.Deny {
+				t.Errorf("expected %s (%s) to be allowed %v on /", token, bot.Category, !rule.Deny)
+			}
+
+			if group.Allowed("/admin") && !rule.Deny && len(rule.Disallow) > 0 {
+				t.Errorf("expected %s to be disallowed on /admin", token)
+			}
+		}
+	}
+}
+
+func TestRobotsPolicyCaseInsensitiveBots(t *testing.T) {
+	t.Parallel()
+
+	policy := &RobotsPolicy{
+		Bots: map[string]RobotsRule{
+			"GPTBot":   {Deny: true},
+			"gptbot":   {Disallow: []string{"/private"}},
+			"MyCustom": {Deny: true},
+			"mycustom": {Disallow: []string{"/private"}},
+		},
+	}
+
+	// Tokens that only differ in case resolve to the first in sorted order,
+	// whatever the map order
+	expected := "User-agent: GPTBot\nUser-agent: MyCustom\nDisallow: /\n\nUser-agent: *\nAllow: /\n"
+
+	for range 20 {
+		if got := policy.Generate(); got != expected {
+			t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+		}
+	}
+}
+
+func TestRobotsPolicyCrawlerVariants(t *testing.T) {
+	t.Parallel()
+
+	// AdsBot-Google ignores "*" groups, so the policy must name it
+	policy := &RobotsPolicy{
+		Categories: map[BotCategory]RobotsRule{
+			BotSearchEngine: {Disallow: []string{"/private"}},
+		},
+		Bots: map[string]RobotsRule{
+			"Googlebot-Image": {Deny: true},
+		},
+	}
+
+	robotsTxt := policy.Generate()
+	robots := ParseRobots([]byte(robotsTxt))
+
+	for _, token := range []string{"AdsBot-Google", "Mediapartners-Google", "Storebot-Google", "adidxbot"} {
+		if !strings.Contains(robotsTxt, "User-agent: "+token+"\n") {
+			t.Errorf("expected robots.txt to name %s, but got:\n%s", token, robotsTxt)
+		}
+
+		if group, ok := robots.group(token); !ok || group.Allowed("/private") {
+			t.Errorf("expected %s to be disallowed /private", token)
+		}
+	}
+
+	if group, ok := robots.group("Googlebot-Image"); !ok || group.Allowed("/") {
+		t.Errorf("expected Googlebot-Image to be denied, but got:\n%s", robotsTxt)
+	}
+}
+
+func TestRobotsPolicyAllowAll(t *testing.T) {
+	t.Parallel()
+
+	robotsTxt := (&RobotsPolicy{}).Generate()
+	if robotsTxt != "User-agent: *\nAllow: /\n" {
+		t.Errorf("expected a single allow group, but got:\n%s", robotsTxt)
+	}
+}
+
+func TestKnownBots(t *testing.T) {
+	t.Parallel()
+
+	bots := KnownBots()
+	if len(bots) == 0 {
+		t.Fatal("expected known bots")
+	}
+
+	names := map[string]bool{}
+
+	for _, bot := range bots {
+		if names[bot.Name] {
+			t.Errorf("expected bot %q to be listed once", bot.Name)
+		}
+
+		names[bot.Name] = true
+
+		if bot.Category == BotNone {
+			t.Errorf("expected bot %q to have a category", bot.Name)
+		}
+
+		for _, token := range bot.Tokens {
+			if strings.ContainsAny(token, " /") {
+				t.Errorf("expected token %q of %q to be a valid product token", token, bot.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package useragent
+
+import (
+	"testing"
+	"time"
+)
+
+const testRobots = `# Example robots.txt
+User-agent: Googlebot
+User-agent: bingbot
+Disallow: /private/
+Allow: /private/public$
+Crawl-delay: 2
+
+user-agent: GPTBot
+disallow: /
+
+User-Agent: *
+Disallow: /search
+Disallow: /*.pdf$
+Allow: /search/about
+Crawl-delay: 0.5
+
+User-agent: googlebot
+Disallow: /drafts
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestRobotsAllowed(t *testing.T) {
+	robots := ParseRobots([]byte(testRobots))
+
+	googlebot := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	bingbot := Parse("Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)")
+	gptbot := Parse("Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)")
+	ahrefs := Parse("Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)")
+	chrome := Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	testCases := []struct {
+		name    string
+		ua      *UserAgent
+		path    string
+		allowed bool
+	}{
+		{name: "Googlebot root", ua: googlebot, path: "/", allowed: true},
+		{name: "Googlebot private", ua: googlebot, path: "/private/page", allowed: false},
+		{name: "Googlebot longest allow wins", ua: googlebot, path: "/private/public", allowed: true},
+		{name: "Googlebot anchored allow", ua: googlebot, path: "/private/public/more", allowed: false},
+		{name: "Googlebot combined groups", ua: googlebot, path: "/drafts/1", allowed: false},
+		{name: "Googlebot ignores star group", ua: googlebot, path: "/search", allowed: true},
+		{name: "Bingbot shares group", ua: bingbot, path: "/private/page", allowed: false},
+		{name: "Bingbot not in second Googlebot group", ua: bingbot, path: "/drafts/1", allowed: true},
+		{name: "GPTBot disallowed", ua: gptbot, path: "/anything", allowed: false},
+		{name: "robots.txt always allowed", ua: gptbot, path: "/robots.txt", allowed: true},
+		{name: "Other bot uses star group", ua: ahrefs, path: "/search?q=go", allowed: false},
+		{name: "Other bot longer allow", ua: ahrefs, path: "/search/about", allowed: true},
+		{name: "Other bot wildcard", ua: ahrefs, path: "/files/report.pdf", allowed: false},
+		{name: "Other bot wildcard anchored", ua: ahrefs, path: "/files/report.pdf?download=1", allowed: true},
+		{name: "Browser uses star group", ua: chrome, path: "/search", allowed: false},
+		{name: "Empty path", ua: ahrefs, path: "", allowed: true},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := robots.Allowed(tc.ua, tc.path); got != tc.allowed {
+				t.Errorf("expected Allowed(%q) to be %v, but got %v", tc.path, tc.allowed, got)
+			}
+		})
+	}
+}
+
+func TestRobotsCrawlDelay(t *testing.T) {
+	t.Parallel()
+
+	robots := ParseRobots([]byte(testRobots))
+
+	delay, ok := robots.Group(Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")).CrawlDelay()
+	if !ok || delay != 2*time.Second {
+		t.Errorf("expected crawl delay 2s, but got %v (%v)", delay, ok)
+	}
+
+	delay, ok = robots.Group(Parse("Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)")).CrawlDelay()
+	if !ok || delay != 500*time.Millisecond {
+		t.Errorf("expected crawl delay 500ms, but got %v (%v)", delay, ok)
+	}
+
+	_, ok = robots.Group(Parse("Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)")).CrawlDelay()
+	if ok {
+		t.Error("expected no crawl delay for GPTBot")
+	}
+
+	if sitemaps := robots.Sitemaps(); len(sitemaps) != 1 || sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("expected one sitemap, but got %v", sitemaps)
+	}
+}
+
+func TestRobotsEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	ua := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+
+	t.Run("empty file allows everything", func(t *testing.T) {
+		t.Parallel()
+
+		if !ParseRobots(nil).Allowed(ua, "/private") {
+			t.Error("expected everything to be allowed")
+		}
+	})
+
+	t.Run("rules before any group are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		if !ParseRobots([]byte("Disallow: /\nUser-agent: other\nDisallow: /")).Allowed(ua, "/page") {
+			t.Error("expected rules outside of a group to be ignored")
+		}
+	})
+
+	t.Run("empty disallow allows everything", func(t *testing.T) {
+		t.Parallel()
+
+		if !ParseRobots([]byte("User-agent: *\nDisallow:")).Allowed(ua, "/page") {
+			t.Error("expected an empty disallow to allow everything")
+		}
+	})
+
+	t.Run("percent encoding", func(t *testing.T) {
+		t.Parallel()
+
+		robots := ParseRobots([]byte("User-agent: *\nDisallow: /caf%c3%a9\nDisallow: /ümlaut"))
+		if robots.Allowed(ua, "/café") {
+			t.Error("expected /café to match the percent-encoded pattern")
+		}
+
+		if robots.Allowed(ua, "/%C3%BCmlaut") {
+			t.Error("expected /%C3%BCmlaut to match the UTF-8 pattern")
+		}
+	})
+
+	t.Run("user-agent with version", func(t *testing.T) {
+		t.Parallel()
+
+		if ParseRobots([]byte("User-agent: Googlebot/2.1\nDisallow: /")).Allowed(ua, "/page") {
+			t.Error("expected the version in the user-agent line to be ignored")
+		}
+	})
+}
+
+func TestRobotsToken(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		token     string
+	}{
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			token:     "Googlebot",
+		},
+		{
+			name:      "ClaudeBot",
+			userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ClaudeBot/1.0; +claudebot@anthropic.com)",
+			token:     "ClaudeBot",
+		},
+		{
+			name:      "Facebook",
+			userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)",
+			token:     "facebookexternalhit",
+		},
+		{
+			name:      "Generic crawler",
+			userAgent: "Mozilla/5.0 (compatible; MyCrawler/1.0)",
+			token:     "MyCrawler",
+		},
+		{
+			name:      "Browser",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			token:     "",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Parse(tc.userAgent).RobotsToken(); got != tc.token {
+				t.Errorf("expected token %q, but got %q", tc.token, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package useragent
+
+import (
+	"strconv"
+	"strings"
+)
+
+// tokenMatchKind is how a tokenCondition matches a user agent.
+type tokenMatchKind int
+
+const (
+	// matchProduct matches products whose name equals the value.
+	matchProduct tokenMatchKind = iota
+	// matchPrefix matches products whose name starts with the value.
+	matchPrefix
+	// matchContains matches products whose name contains the value.
+	matchContains
+	// matchComment matches comments containing the value as a word or
+	// phrase, so "tor" matches "(Tor; Linux)" but not "(Motorola)".
+	matchComment
+)
+
+// tokenCondition matches a user agent by its products or comments rather
+// than by substrings of the whole string.
+type tokenCondition struct {
+	kind  tokenMatchKind
+	value string
+	// version restricts matchProduct to products whose version has this
+	// prefix, e.g. "7" for "Trident/7.0".
+	version string
+}
+
+// tokenRule matches a user agent if any of its conditions matches.
+type tokenRule []tokenCondition
+
+// product returns a rule matching products named any of names. A name may
+// include a version prefix, "trident/7" matches "Trident/7.0".
+func product(names ...string) tokenRule {
+	rule := make(tokenRule, 0, len(names))
+
+	for _, name := range names {
+		name, version, _ := strings.Cut(strings.ToLower(name), "/")
+		rule = append(rule, tokenCondition{kind: matchProduct, value: name, version: version})
+	}
+
+	return rule
+}
+
+// prefix returns a rule matching products whose name starts with any of
+// prefixes.
+func prefix(prefixes ...string) tokenRule {
+	return newTokenRule(matchPrefix, prefixes)
+}
+
+// contains returns a rule matching products whose name contains any of
+// substrings.
+func contains(substrings ...string) tokenRule {
+	return newTokenRule(matchContains, substrings)
+}
+
+// comment returns a rule matching comments that contain any of words.
+func comment(words ...string) tokenRule {
+	return newTokenRule(matchComment, words)
+}
+
+// anyOf returns a rule matching if any of rules matches.
+func anyOf(rules ...tokenRule) tokenRule {
+	var rule tokenRule
+	for _, r := range rules {
+		rule = append(rule, r...)
+	}
+
+	return rule
+}
+
+func newTokenRule(kind tokenMatchKind, values []string) tokenRule {
+	rule := make(tokenRule, 0, len(values))
+	for _, value := range values {
+		rule = append(rule, tokenCondition{kind: kind, value: strings.ToLower(value)})
+	}
+
+	return rule
+}
+
+// match returns the byte offsets of the first condition that matches the
+// tokens, or nil if none does.
+func (r tokenRule) match(t *uaTokens) []int {
+	for i := range r {
+		if loc := r[i].match(t); loc != nil {
+			return loc
+		}
+	}
+
+	return nil
+}
+
+// FindStringIndex tokenizes s and returns the byte offsets of the first
+// condition that matches it, like the method of regexp.Regexp.
+func (r tokenRule) FindStringIndex(s string) []int {
+	t := tokenize(s)
+
+	return r.match(&t)
+}
+
+// String returns the conditions of the rule, e.g.
+// `product(chrome) | prefix(edg) | comment("opera mini")`.
+func (r tokenRule) String() string {
+	conditions := make([]string, 0, len(r))
+	for i := range r {
+		conditions = append(conditions, r[i].String())
+	}
+
+	return strings.Join(conditions, " | ")
+}
+
+func (c *tokenCondition) match(t *uaTokens) []int {
+	if c.kind == matchComment {
+		for i := range t.segments {
+			if offset := wordIndex(t.segments[i].lower, c.value); offset >= 0 {
+				start := t.segments[i].start + offset
+
+				return []int{start, start + len(c.value)}
+			}
+		}
+
+		return nil
+	}
+
+	for i := range t.products {
+		p := &t.products[i]
+
+		var matched bool
+
+		switch c.kind {
+		case matchProduct:
+			matched = p.lowerName == c.value && (c.version == "" || versionHasPrefix(p.version, c.version))
+		case matchPrefix:
+			matched = strings.HasPrefix(p.lowerName, c.value)
+		case matchContains:
+			matched = strings.Contains(p.lowerName, c.value)
+		case matchComment:
+		}
+
+		if matched {
+			return []int{p.start, p.start + len(p.name)}
+		}
+	}
+
+	return nil
+}
+
+func (c *tokenCondition) String() string {
+	switch c.kind {
+	case matchProduct:
+		if c.version != "" {
+			return "product(" + c.value + "/" + c.version + ")"
+		}
+
+		return "product(" + c.value + ")"
+	case matchPrefix:
+		return "prefix(" + c.value + ")"
+	case matchContains:
+		return "contains(" + c.value + ")"
+	case matchComment:
+		return "comment(" + strconv.Quote(c.value) + ")"
+	}
+
+	return ""
+}
+
+// wordIndex returns the index of the first occurrence of word in s that is
+// not part of a longer word, or -1 if there is none.
+func wordIndex(s, word string) int {
+	for offset := 0; offset+len(word) <= len(s); {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return -1
+		}
+
+		start, end := offset+i, offset+i+len(word)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return start
+		}
+
+		offset = start + 1
+	}
+
+	return -1
+}
@@ -0,0 +1,162 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestTokenRule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rule      tokenRule
+		userAgent string
+		match     string
+	}{
+		{"product", product("firefox"), "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox"},
+		{"product is case insensitive", product("FIREFOX"), "firefox/121.0", "firefox"},
+		{"product in a comment", product("googlebot"), "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "Googlebot"},
+		{"product name must be equal", product("tor"), "Mozilla/5.0 (Windows NT 10.0) SiteMonitor/1.0", ""},
+		{"product version prefix", product("trident/7"), "Mozilla/5.0 (Windows NT 10.0; Trident/7.0; rv:11.0) like Gecko", "Trident"},
+		{"product version prefix mismatch", product("trident/7"), "Mozilla/4.0 (compatible; MSIE 9.0; Trident/5.0)", ""},
+		{"prefix", prefix("edg"), "Mozilla/5.0 (Linux; Android 10) Chrome/120.0.0.0 Mobile Safari/537.36 EdgA/120.0.0.0", "EdgA"},
+		{"prefix does not match the middle of a name", prefix("arc"), "Mozilla/5.0 Search/1.0", ""},
+		{"contains", contains("crawler"), "Mozilla/5.0 (compatible; MyCrawler/1.0)", "MyCrawler"},
+		{"comment word", comment("msie"), "Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 6.1)", "MSIE"},
+		{"comment phrase", comment("opera mini"), "Opera/9.80 (J2ME/MIDP; Opera Mini/5.1.21214/28.2725; U; en) Presto/2.8.119", "Opera Mini"},
+		{"comment word boundary", comment("tor"), "Mozilla/5.0 (Linux; Android 13; motorola edge 30)", ""},
+		{"comment does not match products", comment("firefox"), "Mozilla/5.0 Firefox/121.0", ""},
+		{"unclosed comment", comment("msie"), "Mozilla/4.0 (compatible; MSIE 6.0", "MSIE"},
+		{"any of", anyOf(product("chrome"), product("crios")), "Mozilla/5.0 (iPhone) CriOS/120.0.0.0 Mobile/15E148", "CriOS"},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			loc := tc.rule.FindStringIndex(tc.userAgent)
+			if tc.match == "" {
+				if loc != nil {
+					t.Errorf("expected %s not to match, but it matched %q", tc.rule, tc.userAgent[loc[0]:loc[1]])
+				}
+
+				return
+			}
+
+			if loc == nil {
+				t.Fatalf("expected %s to match %q", tc.rule, tc.match)
+			}
+
+			if got := tc.userAgent[loc[0]:loc[1]]; got != tc.match {
+				t.Errorf("expected %s to match %q, but got %q", tc.rule, tc.match, got)
+			}
+		})
+	}
+}
+
+func TestTokenRuleString(t *testing.T) {
+	t.Parallel()
+
+	rule := anyOf(product("chrome", "trident/7"), prefix("edg"), contains("bot"), comment("opera mini"))
+
+	want := `product(chrome) | product(trident/7) | prefix(edg) | contains(bot) | comment("opera mini")`
+	if got := rule.String(); got != want {
+		t.Errorf("expected %q, but got %q", want, got)
+	}
+}
+
+// TestTokenBoundaryRegressions holds user agents that substring rules
+// misclassified, because a rule matched inside an unrelated word or token.
+func TestTokenBoundaryRegressions(t *testing.T) {
+	// Clients no rule recognizes get a generic browser from their first
+	// product, but are still not valid browsers
+	testCases := []struct {
+		name      string
+		userAgent string
+		browser   string
+		isBot     bool
+	}{
+		{
+			name:      "tor inside motorola",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; motorola edge 30) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			browser:   "Chrome",
+		},
+		{
+			name:      "tor inside Monitor",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 SiteMonitor/2.1",
+			browser:   "Chrome",
+		},
+		{
+			name:      "tor inside Navigator",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; WOW64; rv:52.0) Gecko/20100101 Firefox/52.0 Navigator/9.0",
+			browser:   "Firefox",
+		},
+		{
+			name:      "tor inside Editor",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15 PhotoEditor/3.1",
+			browser:   "Safari",
+		},
+		{
+			name:      "arc/ at the end of another product",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15 NewsArc/3.2",
+			browser:   "Safari",
+		},
+		{
+			name:      "edge inside a device model",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; edge 20 pro) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			browser:   "Chrome",
+		},
+		{
+			name:      "msn inside an app name",
+			userAgent: "MSN Money/1.0 CFNetwork/1410.0.3 Darwin/22.6.0",
+			browser:   "Money",
+			isBot:     true,
+		},
+		{
+			name:      "api inside an app name",
+			userAgent: "RapidWeaver/9.2 (Macintosh; Intel Mac OS X 14_2)",
+			browser:   "RapidWeaver",
+			isBot:     true,
+		},
+		{
+			name:      "google inside the Google app",
+			userAgent: "com.google.GoogleMobile/300.0 iPhone/17.2 hw/iPhone15_2",
+			browser:   "com.google.GoogleMobile",
+			isBot:     true,
+		},
+		{
+			name:      "Screaming Frog outside of a comment",
+			userAgent: "Screaming Frog SEO Spider/19.0",
+			browser:   "[Bot] Screaming Frog",
+			isBot:     true,
+		},
+		{
+			name:      "Screaming Frog in a comment",
+			userAgent: "Mozilla/5.0 (compatible; Screaming Frog SEO Spider/19.0)",
+			browser:   "[Bot] Screaming Frog",
+			isBot:     true,
+		},
+		{
+			name:      "safari inside another product",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) NotSafari/1.0 Gecko/20100101 Firefox/121.0",
+			browser:   "Firefox",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.Browser() != tc.browser {
+				t.Errorf("expected browser %q, but got %q", tc.browser, ua.Browser())
+			}
+
+			if ua.IsBot(true) != tc.isBot {
+				t.Errorf("expected IsBot(true) to be %v, but got %v", tc.isBot, ua.IsBot(true))
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxLength is the length limit in bytes of DefaultSanitizeOptions and
+// DefaultParseOptions. Real user agents rarely exceed a few hundred bytes.
+const DefaultMaxLength = 1024
+
+// ErrUserAgentTooLong is returned by ParseWithOptions for user agents longer
+// than the MaxLength of its options.
+var ErrUserAgentTooLong = errors.New("useragent: user agent too long")
+
+// EscapeFormat selects how Sanitize writes the unsafe characters it keeps.
+type EscapeFormat string
+
+// Escape formats supported by Sanitize.
+const (
+	// EscapeBackslash writes unsafe characters as escapes such as "\x1b"
+	// and "\u202e", and doubles backslashes so the result stays
+	// unambiguous.
+	EscapeBackslash EscapeFormat = "backslash"
+	// EscapePercent writes the UTF-8 bytes of unsafe characters as escapes
+	// such as "%1B", and "%" as "%25".
+	EscapePercent EscapeFormat = "percent"
+	// EscapeReplacement replaces unsafe characters with U+FFFD.
+	EscapeReplacement EscapeFormat = "replacement"
+	// EscapeNone keeps unsafe characters as they are.
+	EscapeNone EscapeFormat = "none"
+)
+
+// String returns the escape format as a string.
+func (f EscapeFormat) String() string {
+	return string(f)
+}
+
+// AllEscapeFormats returns every escape format Sanitize supports.
+func AllEscapeFormats() []EscapeFormat {
+	return []EscapeFormat{
+		EscapeBackslash,
+		EscapePercent,
+		EscapeReplacement,
+		EscapeNone,
+	}
+}
+
+// SanitizeOptions controls how Sanitize cleans a user agent.
+type SanitizeOptions struct {
+	// MaxLength is the maximum length of the result in bytes. Longer
+	// results are cut without splitting a character or an escape. Zero or
+	// less disables the limit.
+	MaxLength int
+	// StripControl removes unsafe characters instead of escaping them.
+	StripControl bool
+	// Escape is how unsafe characters are written if StripControl is
+	// false. An empty Escape uses EscapeBackslash.
+	Escape EscapeFormat
+}
+
+// DefaultSanitizeOptions returns options that escape unsafe characters with
+// backslashes and limit the result to DefaultMaxLength bytes.
+func DefaultSanitizeOptions() SanitizeOptions {
+	return SanitizeOptions{MaxLength: DefaultMaxLength, Escape: EscapeBackslash}
+}
+
+// Sanitize makes a user agent safe to write to logs, terminals and
+// databases. Invalid UTF-8 is replaced with U+FFFD, unsafe characters are
+// stripped or escaped and the result is cut to the maximum length. Unsafe
+// characters are control characters, which include newlines and the start
+// of terminal escape sequences, line and paragraph separators and
+// bidirectional text controls. A nil options uses DefaultSanitizeOptions.
+func Sanitize(userAgent string, options *SanitizeOptions) string {
+	if options == nil {
+		defaultOptions := DefaultSanitizeOptions()
+		options = &defaultOptions
+	}
+
+	escapeChar, escapedEscapeChar := options.escapeChar()
+
+	var b strings.Builder
+
+	for i := 0; i < len(userAgent); {
+		r, size := utf8.DecodeRuneInString(userAgent[i:])
+		piece := userAgent[i : i+size]
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			piece = string(utf8.RuneError)
+		case isUnsafeRune(r):
+			piece = options.escape(r)
+		case r == escapeChar:
+			piece = escapedEscapeChar
+		}
+
+		if options.MaxLength > 0 && b.Len()+len(piece) > options.MaxLength {
+			break
+		}
+
+		b.WriteString(piece)
+
+		i += size
+	}
+
+	return b.String()
+}
+
+// escapeChar returns the character that starts an escape and its own
+// escape, or -1 if the options do not write escapes.
+func (o *SanitizeOptions) escapeChar() (rune, string) {
+	if o.StripControl {
+		return -1, ""
+	}
+
+	switch o.Escape {
+	case EscapeBackslash:
+		return '\\', `\\`
+	case EscapePercent:
+		return '%', "%25"
+	case EscapeReplacement, EscapeNone:
+		return -1, ""
+	}
+
+	return '\\', `\\`
+}
+
+// escape returns what Sanitize writes for the unsafe character r.
+func (o *SanitizeOptions) escape(r rune) string {
+	if o.StripControl {
+		return ""
+	}
+
+	switch o.Escape {
+	case EscapeBackslash:
+		return backslashEscape(r)
+	case EscapePercent:
+		var buf [utf8.UTFMax]byte
+
+		var b strings.Builder
+		for _, c := range buf[:utf8.EncodeRune(buf[:], r)] {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+
+		return b.String()
+	case EscapeReplacement:
+		return string(utf8.RuneError)
+	case EscapeNone:
+		return string(r)
+	}
+
+	return backslashEscape(r)
+}
+
+func backslashEscape(r rune) string {
+	if r < utf8.RuneSelf {
+		return fmt.Sprintf(`\x%02x`, r)
+	}
+
+	return fmt.Sprintf(`\u%04x`, r)
+}
+
+// isUnsafeRune returns true for characters that can forge log lines or
+// change how a terminal or editor displays the text around them.
+func isUnsafeRune(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029' || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// ParseOptions holds the limits ParseWithOptions applies before parsing.
+type ParseOptions struct {
+	// MaxLength is the maximum length of the user agent in bytes. Zero or
+	// less disables the limit.
+	MaxLength int
+	// Truncate cuts longer user agents to MaxLength instead of returning
+	// ErrUserAgentTooLong.
+	Truncate bool
+}
+
+// DefaultParseOptions returns options that reject user agents longer than
+// DefaultMaxLength bytes.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{MaxLength: DefaultMaxLength}
+}
+
+// ParseWithOptions parses a user agent like Parse, but checks its length
+// first so oversized input never reaches the rules. Truncated user agents
+// are parsed and returned by UserAgent in their truncated form. A nil
+// options uses DefaultParseOptions.
+func ParseWithOptions(userAgent string, options *ParseOptions) (*UserAgent, error) {
+	if options == nil {
+		defaultOptions := DefaultParseOptions()
+		options = &defaultOptions
+	}
+
+	if options.MaxLength > 0 && len(userAgent) > options.MaxLength {
+		if !options.Truncate {
+			return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrUserAgentTooLong, len(userAgent), options.MaxLength)
+		}
+
+		userAgent = truncateUTF8(userAgent, options.MaxLength)
+	}
+
+	return parse(userAgent, nil), nil
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
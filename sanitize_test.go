@@ -0,0 +1,153 @@
+package useragent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitize(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		options   *SanitizeOptions
+		expected  string
+	}{
+		{
+			name:      "clean",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0",
+			expected:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0",
+		},
+		{
+			name:      "forged log line",
+			userAgent: "curl/8.4.0\r\n127.0.0.1 - admin [01/Jan/2024] \"GET /admin\"",
+			expected:  `curl/8.4.0\x0d\x0a127.0.0.1 - admin [01/Jan/2024] "GET /admin"`,
+		},
+		{
+			name:      "terminal escape sequence",
+			userAgent: "Mozilla/5.0 \x1b[2J\x1b]0;owned\x07",
+			expected:  `Mozilla/5.0 \x1b[2J\x1b]0;owned\x07`,
+		},
+		{
+			name:      "bidirectional override",
+			userAgent: "Mozilla/5.0 \u202egnp.exe",
+			expected:  `Mozilla/5.0 \u202egnp.exe`,
+		},
+		{
+			name:      "backslash",
+			userAgent: `Mozilla/5.0 \x1b`,
+			expected:  `Mozilla/5.0 \\x1b`,
+		},
+		{
+			name:      "invalid UTF-8",
+			userAgent: "Mozilla/5.0 \xff\xfeApp/1.0",
+			expected:  "Mozilla/5.0 \uFFFD\uFFFDApp/1.0",
+		},
+		{
+			name:      "non-ASCII text",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; Téléphone)",
+			expected:  "Mozilla/5.0 (Linux; Android 13; Téléphone)",
+		},
+		{
+			name:      "strip",
+			userAgent: "curl/8.4.0\r\n\x1b[31mred\u202e",
+			options:   &SanitizeOptions{StripControl: true},
+			expected:  "curl/8.4.0[31mred",
+		},
+		{
+			name:      "percent",
+			userAgent: "100% curl/8.4.0\n\u202e",
+			options:   &SanitizeOptions{Escape: EscapePercent},
+			expected:  "100%25 curl/8.4.0%0A%E2%80%AE",
+		},
+		{
+			name:      "replacement",
+			userAgent: `curl\8.4.0` + "\n",
+			options:   &SanitizeOptions{Escape: EscapeReplacement},
+			expected:  `curl\8.4.0` + "\uFFFD",
+		},
+		{
+			name:      "none",
+			userAgent: "curl/8.4.0\n\xff",
+			options:   &SanitizeOptions{Escape: EscapeNone},
+			expected:  "curl/8.4.0\n\uFFFD",
+		},
+		{
+			name:      "empty escape format",
+			userAgent: "curl/8.4.0\n",
+			options:   &SanitizeOptions{},
+			expected:  `curl/8.4.0\x0a`,
+		},
+		{
+			name:      "max length",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0)",
+			options:   &SanitizeOptions{MaxLength: 11},
+			expected:  "Mozilla/5.0",
+		},
+		{
+			name:      "max length does not split an escape",
+			userAgent: "curl/8.4.0\n",
+			options:   &SanitizeOptions{MaxLength: 12},
+			expected:  "curl/8.4.0",
+		},
+		{
+			name:      "max length does not split a character",
+			userAgent: "Téléphone",
+			options:   &SanitizeOptions{MaxLength: 2},
+			expected:  "T",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Sanitize(tc.userAgent, tc.options); got != tc.expected {
+				t.Errorf("expected %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeDefaultMaxLength(t *testing.T) {
+	t.Parallel()
+
+	got := Sanitize(strings.Repeat("é", 50*1024), nil)
+	if len(got) != DefaultMaxLength || !utf8.ValidString(got) {
+		t.Errorf("expected %d bytes of valid UTF-8, but got %d bytes", DefaultMaxLength, len(got))
+	}
+}
+
+func TestParseWithOptions(t *testing.T) {
+	t.Parallel()
+
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	oversized := chrome + " " + strings.Repeat("x", 50*1024)
+
+	ua, err := ParseWithOptions(chrome, nil)
+	if err != nil || ua.Browser() != "Chrome" {
+		t.Fatalf("expected Chrome, but got %v, %v", ua, err)
+	}
+
+	if _, err := ParseWithOptions(oversized, nil); !errors.Is(err, ErrUserAgentTooLong) {
+		t.Errorf("expected ErrUserAgentTooLong, but got %v", err)
+	}
+
+	ua, err = ParseWithOptions(oversized, &ParseOptions{MaxLength: len(chrome), Truncate: true})
+	if err != nil || ua.UserAgent() != chrome || ua.Browser() != "Chrome" {
+		t.Errorf("expected the truncated Chrome user agent, but got %v, %v", ua, err)
+	}
+
+	ua, err = ParseWithOptions(oversized, &ParseOptions{})
+	if err != nil || ua.UserAgent() != oversized {
+		t.Errorf("expected no limit, but got %v", err)
+	}
+
+	ua, err = ParseWithOptions("Téléphone", &ParseOptions{MaxLength: 2, Truncate: true})
+	if err != nil || ua.UserAgent() != "T" {
+		t.Errorf("expected %q, but got %v, %v", "T", ua, err)
+	}
+}
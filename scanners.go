@@ -0,0 +1,62 @@
+package useragent
+
+import (
+	"regexp"
+)
+
+// Scanner describes a security scanner or attack tool.
+type Scanner struct {
+	// Name is the name of the tool, e.g. "sqlmap".
+	Name string
+	// Version is the version of the tool, or the zero Version if the user
+	// agent does not include it.
+	Version Version
+}
+
+// Scanners returns the names of the security scanners the parser can
+// detect.
+func Scanners() []string {
+	var names []string
+
+	for i := range browsers {
+		if bp := &browsers[i]; bp.scanner != "" {
+			names = append(names, bp.scanner)
+		}
+	}
+
+	return names
+}
+
+// Scanner returns the security scanner that sent the user agent, if it is a
+// known scanner or attack tool.
+func (ua *UserAgent) Scanner() (Scanner, bool) {
+	return ua.scanner, ua.scanner.Name != ""
+}
+
+// newScanner returns the scanner a browser pattern detects, or the zero
+// Scanner if it is not a scanner.
+func newScanner(bp *browserPattern, userAgent string) Scanner {
+	if bp.scanner == "" {
+		return Scanner{}
+	}
+
+	var version Version
+	if bp.scannerVersion != nil {
+		version = parseVersion(firstSubmatch(bp.scannerVersion, userAgent))
+	}
+
+	return Scanner{Name: bp.scanner, Version: version}
+}
+
+// compileScanner returns the browser pattern of a security scanner, which
+// reads the version with versionPattern if it is not empty.
+func compileScanner(name string, rule tokenRule, versionPattern string) browserPattern {
+	bp := compileBot("[Bot] "+name, rule, BotScanner)
+	bp.scanner = name
+
+	if versionPattern != "" {
+		bp.scannerVersion = regexp.MustCompile(`(?i)` + versionPattern)
+	}
+
+	return bp
+}
@@ -0,0 +1,95 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	testCases := []struct {
+		userAgent string
+		name      string
+		version   string
+	}{
+		{"sqlmap/1.7.2#stable (https://sqlmap.org)", "sqlmap", "1.7.2"},
+		{"Mozilla/5.00 (Nikto/2.1.6) (Evasions:None) (Test:000001)", "Nikto", "2.1.6"},
+		{"Mozilla/5.0 (compatible; Nmap Scripting Engine; https://nmap.org/book/nse.html)", "Nmap Scripting Engine", ""},
+		{"masscan/1.3 (https://github.com/robertdavidgraham/masscan)", "masscan", "1.3"},
+		{"Mozilla/5.0 zgrab/0.x", "ZGrab", "0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Nuclei/v3.1.0", "Nuclei", "3.1.0"},
+		{"WPScan v3.8.25 (https://wpscan.com/wordpress-security-scanner)", "WPScan", "3.8.25"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 (Acunetix)", "Acunetix", ""},
+		{"Mozilla/5.0 (compatible; Burp Collaborator)", "Burp Suite", ""},
+		{"Mozilla/5.0 [en] (X11, U; OpenVAS-VT 9.0.3)", "OpenVAS", "9.0.3"},
+		{"Mozilla/5.0 (compatible; Nessus)", "Nessus", ""},
+		{"Mozilla/5.0 (compatible; Netsparker/6.8)", "Netsparker", "6.8"},
+		{"Arachni/v1.5.1", "Arachni", "1.5.1"},
+		{"w3af.org", "w3af", ""},
+		{"commix/v3.9 (https://commixproject.com)", "commix", "3.9"},
+		{"Wfuzz/3.1.0", "Wfuzz", "3.1.0"},
+		{"DirBuster-1.0-RC1 (http://www.owasp.org/index.php/Category:OWASP_DirBuster_Project)", "DirBuster", "1.0"},
+		{"gobuster/3.6", "gobuster", "3.6"},
+		{"feroxbuster/2.10.1", "feroxbuster", "2.10.1"},
+		{"ZmEu", "ZmEu", ""},
+		{"Mozilla/5.0 Jorgee", "Jorgee", ""},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.BotCategory() != BotScanner {
+				t.Fatalf("expected %q to be a scanner, but got %q (%s)", tc.userAgent, ua.BotCategory(), ua.Browser())
+			}
+
+			scanner, ok := ua.Scanner()
+			if !ok || scanner.Name != tc.name || scanner.Version.String() != tc.version {
+				t.Errorf("expected %s %q, but got %+v, %v", tc.name, tc.version, scanner, ok)
+			}
+
+			if c := ua.Classify(); c.Class != ClassBot || !c.Has(ReasonScanner) {
+				t.Errorf("expected a scanner classification, but got %s", c)
+			}
+		})
+	}
+}
+
+func TestScannerNotScanner(t *testing.T) {
+	t.Parallel()
+
+	for _, userAgent := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"curl/8.4.0",
+	} {
+		if scanner, ok := Parse(userAgent).Scanner(); ok {
+			t.Errorf("expected %q not to be a scanner, but got %+v", userAgent, scanner)
+		}
+	}
+}
+
+func TestScannersHaveRules(t *testing.T) {
+	t.Parallel()
+
+	// Every scanner rule names the scanner, so Scanner reports it
+	count := 0
+
+	for i := range browsers {
+		bp := &browsers[i]
+		if bp.category != BotScanner {
+			continue
+		}
+
+		count++
+
+		if bp.scanner == "" {
+			t.Errorf("expected the rule %q to name a scanner", bp.name)
+		}
+	}
+
+	if len(Scanners()) != count {
+		t.Errorf("expected %d scanners, but got %d", count, len(Scanners()))
+	}
+}
@@ -0,0 +1,257 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ThreatKind classifies an attack payload found in a user agent.
+type ThreatKind string
+
+// Threat kinds reported by Threats.
+const (
+	// ThreatLog4Shell is a Log4j JNDI lookup such as
+	// "${jndi:ldap://example.com/a}", including lookups obfuscated with
+	// nested "${lower:j}" or "${::-j}" expressions.
+	ThreatLog4Shell ThreatKind = "log4shell"
+	// ThreatShellshock is a Bash function definition such as "() { :; };"
+	// that exploits CVE-2014-6271 in CGI scripts.
+	ThreatShellshock ThreatKind = "shellshock"
+	// ThreatSQLInjection is an SQL fragment such as "' OR 1=1--" or
+	// "UNION SELECT".
+	ThreatSQLInjection ThreatKind = "sql_injection"
+	// ThreatXSS is an HTML or JavaScript fragment such as "<script>" aimed at
+	// log viewers and admin panels.
+	ThreatXSS ThreatKind = "xss"
+	// ThreatTemplateInjection is a server-side template expression such as
+	// "{{7*7}}" or "#{...}".
+	ThreatTemplateInjection ThreatKind = "template_injection"
+)
+
+// String returns the threat kind as a string.
+func (k ThreatKind) String() string {
+	return string(k)
+}
+
+// AllThreatKinds returns every threat kind Threats can report.
+func AllThreatKinds() []ThreatKind {
+	return []ThreatKind{
+		ThreatLog4Shell,
+		ThreatShellshock,
+		ThreatSQLInjection,
+		ThreatXSS,
+		ThreatTemplateInjection,
+	}
+}
+
+// Threat is an attack payload found in a user agent.
+type Threat struct {
+	Kind ThreatKind
+	// Payload is the text that matched, after percent-decoding and, for
+	// Log4Shell, after resolving the obfuscating lookups.
+	Payload string
+}
+
+// String returns the threat as "kind: payload".
+func (t Threat) String() string {
+	return t.Kind.String() + ": " + t.Payload
+}
+
+// threatRule checks a decoded user agent for a single kind of payload.
+type threatRule struct {
+	kind  ThreatKind
+	check func(userAgent string) (string, bool)
+}
+
+// Threats looks for attack payloads in the user agent string and returns one
+// finding per kind of payload. Percent-encoded payloads are decoded first.
+// Browsers never send these payloads, so any finding means the request is an
+// attack or a scanner probing for one.
+func (ua *UserAgent) Threats() []Threat {
+	userAgent := percentDecode(ua.userAgent)
+
+	var threats []Threat
+
+	for _, rule := range threatRules {
+		if payload, found := rule.check(userAgent); found {
+			threats = append(threats, Threat{Kind: rule.kind, Payload: payload})
+		}
+	}
+
+	return threats
+}
+
+// HasThreats returns true if the user agent contains any attack payloads.
+func (ua *UserAgent) HasThreats() bool {
+	return len(ua.Threats()) > 0
+}
+
+// checkLog4Shell reports JNDI lookups. Lookups are resolved from the
+// innermost out, so "${${lower:j}ndi:...}" and "${${::-j}${::-n}di:...}"
+// become "${jndi:...}" before the check.
+func checkLog4Shell(userAgent string) (string, bool) {
+	if !strings.Contains(userAgent, "${") {
+		return "", false
+	}
+
+	s := userAgent
+
+	// Every pass resolves at least one lookup, the limit stops crafted
+	// strings from taking long
+	for range 64 {
+		loc := log4jLookupRegEx.FindStringSubmatchIndex(s)
+		if loc == nil {
+			// Log4j also resolves lookups that are cut off by the end of
+			// the header
+			if i := strings.Index(strings.ToLower(s), "${jndi:"); i >= 0 {
+				return s[i:], true
+			}
+
+			return "", false
+		}
+
+		lookup := s[loc[2]:loc[3]]
+		if strings.HasPrefix(strings.ToLower(lookup), "jndi:") {
+			return "${" + lookup + "}", true
+		}
+
+		s = s[:loc[0]] + resolveLog4jLookup(lookup) + s[loc[1]:]
+	}
+
+	return "", false
+}
+
+// resolveLog4jLookup returns the value an attacker expects a Log4j lookup to
+// resolve to: the default after ":-", the argument of lower and upper, or
+// the name of any other lookup.
+func resolveLog4jLookup(lookup string) string {
+	if _, fallback, ok := strings.Cut(lookup, ":-"); ok {
+		return fallback
+	}
+
+	prefix, value, ok := strings.Cut(lookup, ":")
+	if !ok {
+		return lookup
+	}
+
+	switch strings.ToLower(prefix) {
+	case "lower":
+		return strings.ToLower(value)
+	case "upper":
+		return strings.ToUpper(value)
+	case "date":
+		return strings.Trim(value, "'")
+	}
+
+	return lookup
+}
+
+func checkShellshock(userAgent string) (string, bool) {
+	return findThreat(shellshockRegEx, userAgent)
+}
+
+func checkSQLInjection(userAgent string) (string, bool) {
+	return findThreat(sqlInjectionRegEx, userAgent)
+}
+
+func checkXSS(userAgent string) (string, bool) {
+	return findThreat(xssRegEx, userAgent)
+}
+
+// checkTemplateInjection reports template expressions. "${...}" expressions
+// that are Log4Shell payloads are only reported as Log4Shell.
+func checkTemplateInjection(userAgent string) (string, bool) {
+	if payload, found := findThreat(templateInjectionRegEx, userAgent); found {
+		return payload, true
+	}
+
+	if _, found := checkLog4Shell(userAgent); found {
+		return "", false
+	}
+
+	return findThreat(expressionLanguageRegEx, userAgent)
+}
+
+// findThreat returns the first match of re in the user agent.
+func findThreat(re *regexp.Regexp, userAgent string) (string, bool) {
+	payload := re.FindString(userAgent)
+
+	return payload, payload != ""
+}
+
+// percentDecode decodes the %XX escapes of s and leaves invalid escapes as
+// they are.
+func percentDecode(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			b.WriteByte(hexValue(s[i+1])<<4 | hexValue(s[i+2]))
+
+			i += 2
+
+			continue
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// hexValue returns the value of a hexadecimal digit, see isHex.
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
+
+var (
+	// log4jLookupRegEx matches an innermost lookup, one without lookups in
+	// it.
+	log4jLookupRegEx = regexp.MustCompile(`\$\{([^${}]*)\}`)
+
+	shellshockRegEx = regexp.MustCompile(`\(\s*\)\s*\{[^}]*\}?(?:\s*;)?`)
+
+	sqlInjectionRegEx = regexp.MustCompile(`(?i)` +
+		`['"]\s*(?:or|and)\s+['"\w]+\s*(?:=|like)|` +
+		`\bor\s+\d+\s*=\s*\d+|` +
+		`\bunion(?:\s+|/\*.*?\*/)+(?:all(?:\s+|/\*.*?\*/)+)?select\b|` +
+		`\bselect\b[^;]{0,100}\bfrom\b|` +
+		`;\s*(?:drop|insert|update|delete|truncate|alter|exec)\b|` +
+		`\b(?:sleep|pg_sleep|benchmark|extractvalue|updatexml)\s*\(|` +
+		`\bwaitfor\s+delay\b|` +
+		`\binformation_schema\b|` +
+		`['"]\s*;?\s*(?:--|#|/\*)`)
+
+	xssRegEx = regexp.MustCompile(`(?i)` +
+		`<\s*/?\s*(?:script|iframe|svg|img|body|object|embed|math)\b[^>]*>?|` +
+		`\bjavascript\s*:|` +
+		`\bon(?:error|load|mouseover|focus|click|toggle)\s*=|` +
+		`\balert\s*\(|` +
+		`\bdocument\.(?:cookie|domain|location)\b`)
+
+	// templateInjectionRegEx matches Jinja, Twig, Handlebars, ERB, Ruby and
+	// Spring expressions.
+	templateInjectionRegEx = regexp.MustCompile(`\{\{[^}]*\}\}|\{%[^%]*%\}|<%=?[^%]*%>|[#*]\{[^}]*\}`)
+
+	// expressionLanguageRegEx matches Java EL and FreeMarker expressions.
+	expressionLanguageRegEx = regexp.MustCompile(`\$\{[^}]*\}`)
+
+	threatRules = [...]threatRule{
+		{kind: ThreatLog4Shell, check: checkLog4Shell},
+		{kind: ThreatShellshock, check: checkShellshock},
+		{kind: ThreatSQLInjection, check: checkSQLInjection},
+		{kind: ThreatXSS, check: checkXSS},
+		{kind: ThreatTemplateInjection, check: checkTemplateInjection},
+	}
+)
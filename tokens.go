@@ -0,0 +1,261 @@
+package useragent
+
+import (
+	"strings"
+)
+
+// Product is a product token of a user agent with the comments that follow
+// it, e.g. "Mozilla/5.0 (Windows NT 10.0; Win64; x64)" is the product
+// Mozilla with version 5.0 and the comments "Windows NT 10.0", "Win64" and
+// "x64".
+type Product struct {
+	// Name is the product name, or empty for comments that precede every
+	// product.
+	Name string
+	// Version is the text after the slash, or empty if there is none.
+	Version string
+	// Comments are the fields of the comments that follow the product,
+	// separated by semicolons and trimmed. Nested comments are kept in the
+	// field that contains them, parentheses included.
+	Comments []string
+}
+
+// String formats the product as it appears in a user agent.
+func (p Product) String() string {
+	var b strings.Builder
+
+	b.WriteString(p.Name)
+
+	if p.Version != "" {
+		b.WriteByte('/')
+		b.WriteString(p.Version)
+	}
+
+	if len(p.Comments) > 0 {
+		if p.Name != "" {
+			b.WriteByte(' ')
+		}
+
+		b.WriteByte('(')
+		b.WriteString(strings.Join(p.Comments, "; "))
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+// Tokenize splits a user agent into its products and comments following the
+// grammar of RFC 9110 section 10.1.5:
+//
+//	User-Agent = product *( RWS ( product / comment ) )
+//
+// Real user agents often break the grammar, so Tokenize never fails:
+// unbalanced parentheses end the comment at the end of the string, stray
+// separators between products are skipped, and a product name with a space,
+// such as "Opera Mini/5.1" outside a comment, becomes two products.
+func Tokenize(userAgent string) []Product {
+	t := tokenize(userAgent)
+
+	var products []Product
+
+	// Comments before the first product belong to a product without a name
+	offset := 0
+	if len(t.segments) > 0 && t.segments[0].product < 0 {
+		products = append(products, Product{})
+		offset = 1
+	}
+
+	for i := range t.products {
+		if p := &t.products[i]; !p.inComment {
+			products = append(products, Product{Name: p.name, Version: p.version})
+		}
+	}
+
+	for i := range t.segments {
+		p := &products[t.segments[i].product+offset]
+		p.Comments = append(p.Comments, t.segments[i].text)
+	}
+
+	return products
+}
+
+// uaTokens are the product tokens and comments of a user agent, following the
+// grammar of RFC 9110 section 10.1.5: products such as "Firefox/121.0"
+// separated by whitespace, with comments in parentheses.
+type uaTokens struct {
+	// products are the top level products, followed by the products that
+	// start a comment segment, such as "Googlebot/2.1" in
+	// "(compatible; Googlebot/2.1; +http://www.google.com/bot.html)".
+	// Product names in comments may contain spaces, like "Opera Mini".
+	products []productToken
+	// segments are the fields of the comments, separated by ";".
+	segments []commentSegment
+}
+
+// productToken is a product name with an optional version after a slash.
+type productToken struct {
+	name      string
+	lowerName string
+	version   string
+	start     int
+	end       int
+	inComment bool
+}
+
+// commentSegment is a trimmed field of a comment.
+type commentSegment struct {
+	text  string
+	lower string
+	start int
+	// product is the index of the top level product the comment follows, or
+	// -1 if it precedes every product.
+	product int
+}
+
+// tokenize splits a user agent into products and comments, see Tokenize.
+func tokenize(userAgent string) uaTokens {
+	var t uaTokens
+
+	var inComment []productToken
+
+	for i := 0; i < len(userAgent); {
+		c := userAgent[i]
+
+		switch {
+		case c == '(':
+			end := commentEnd(userAgent, i)
+			inComment = t.addComment(userAgent, i+1, end, inComment)
+
+			i = end + 1
+		case isTokenDelimiter(c):
+			i++
+		default:
+			start := i
+			for i < len(userAgent) && !isTokenDelimiter(userAgent[i]) && userAgent[i] != '(' {
+				i++
+			}
+
+			t.products = append(t.products, newProductToken(userAgent, start, i, false))
+		}
+	}
+
+	t.products = append(t.products, inComment...)
+
+	return t
+}
+
+// commentEnd returns the index of the parenthesis closing the comment opened
+// at start, or the end of s if it is not closed. Nested comments and
+// characters escaped with a backslash do not close it.
+func commentEnd(s string, start int) int {
+	depth := 0
+
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s)
+}
+
+// addComment adds the segments of the comment between start and end, and
+// appends the products that start them to products.
+func (t *uaTokens) addComment(s string, start, end int, products []productToken) []productToken {
+	for start < end {
+		next := fieldEnd(s, start, end)
+		segment := s[start:next]
+		trimmed := strings.TrimSpace(segment)
+
+		if trimmed != "" {
+			offset := start + strings.Index(segment, trimmed)
+			t.segments = append(t.segments, commentSegment{
+				text:    trimmed,
+				lower:   strings.ToLower(trimmed),
+				start:   offset,
+				product: len(t.products) - 1,
+			})
+
+			if productEnd, ok := commentProductEnd(trimmed); ok {
+				products = append(products, newProductToken(s, offset, offset+productEnd, true))
+			}
+		}
+
+		start = next + 1
+	}
+
+	return products
+}
+
+// fieldEnd returns the index of the semicolon that ends the comment field at
+// start, or end if it is the last field. Semicolons in nested comments and
+// escaped with a backslash do not end it.
+func fieldEnd(s string, start, end int) int {
+	depth := 0
+
+	for i := start; i < end; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth = max(depth-1, 0)
+		case ';':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return end
+}
+
+// commentProductEnd returns the end of the product that starts a comment
+// segment. A segment starts with a product if it has a version, like
+// "Googlebot/2.1" or "Opera Mini/5.1", or is a single word like "Bytespider".
+// Descriptions such as "Windows NT 10.0" or a device model are not products.
+func commentProductEnd(segment string) (int, bool) {
+	slash := strings.IndexByte(segment, '/')
+	if slash < 0 {
+		return len(segment), !strings.ContainsAny(segment, " \t")
+	}
+
+	if space := strings.IndexAny(segment[slash:], " \t"); space >= 0 {
+		return slash + space, true
+	}
+
+	return len(segment), true
+}
+
+// newProductToken returns the product between start and end of s.
+func newProductToken(s string, start, end int, inComment bool) productToken {
+	name, version, _ := strings.Cut(s[start:end], "/")
+
+	return productToken{
+		name:      name,
+		lowerName: strings.ToLower(name),
+		version:   version,
+		start:     start,
+		end:       end,
+		inComment: inComment,
+	}
+}
+
+// isTokenDelimiter returns true for the characters that separate products.
+func isTokenDelimiter(c byte) bool {
+	return c == ' ' || c == '\t' || c == ';' || c == ',' || c == ')'
+}
+
+// isWordByte returns true for the characters words are made of.
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
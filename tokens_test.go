@@ -0,0 +1,130 @@
+package useragent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		products  []Product
+	}{
+		{
+			name:      "Chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			products: []Product{
+				{Name: "Mozilla", Version: "5.0", Comments: []string{"Windows NT 10.0", "Win64", "x64"}},
+				{Name: "AppleWebKit", Version: "537.36", Comments: []string{"KHTML, like Gecko"}},
+				{Name: "Chrome", Version: "120.0.0.0"},
+				{Name: "Safari", Version: "537.36"},
+			},
+		},
+		{
+			name:      "product without a version",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Tor Browser Firefox/115.0",
+			products: []Product{
+				{Name: "Mozilla", Version: "5.0", Comments: []string{"X11", "Linux x86_64", "rv:109.0"}},
+				{Name: "Gecko", Version: "20100101"},
+				{Name: "Tor"},
+				{Name: "Browser"},
+				{Name: "Firefox", Version: "115.0"},
+			},
+		},
+		{
+			name:      "nested comment",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; moto g stylus 5G (2023)) Chrome/120.0.0.0",
+			products: []Product{
+				{Name: "Mozilla", Version: "5.0", Comments: []string{"Linux", "Android 13", "moto g stylus 5G (2023)"}},
+				{Name: "Chrome", Version: "120.0.0.0"},
+			},
+		},
+		{
+			name:      "semicolons in a nested comment",
+			userAgent: "Foo/1.0 (a; (b; c); d) Bar/2",
+			products: []Product{
+				{Name: "Foo", Version: "1.0", Comments: []string{"a", "(b; c)", "d"}},
+				{Name: "Bar", Version: "2"},
+			},
+		},
+		{
+			name:      "unbalanced comment",
+			userAgent: "Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1",
+			products: []Product{
+				{Name: "Mozilla", Version: "4.0", Comments: []string{"compatible", "MSIE 6.0", "Windows NT 5.1"}},
+			},
+		},
+		{
+			name:      "stray closing parenthesis",
+			userAgent: "curl/8.4.0) extra",
+			products:  []Product{{Name: "curl", Version: "8.4.0"}, {Name: "extra"}},
+		},
+		{
+			name:      "escaped parenthesis",
+			userAgent: `MyApp/1.0 (build \) 42; beta)`,
+			products:  []Product{{Name: "MyApp", Version: "1.0", Comments: []string{`build \) 42`, "beta"}}},
+		},
+		{
+			name:      "semicolons between products",
+			userAgent: "FeedFetcher-Google; (+http://www.google.com/feedfetcher.html)",
+			products: []Product{
+				{Name: "FeedFetcher-Google", Comments: []string{"+http://www.google.com/feedfetcher.html"}},
+			},
+		},
+		{
+			name:      "several comments",
+			userAgent: "Opera/9.80 (J2ME/MIDP; Opera Mini/5.1.21214/28.2725; U; en) (Presto)",
+			products: []Product{
+				{Name: "Opera", Version: "9.80", Comments: []string{"J2ME/MIDP", "Opera Mini/5.1.21214/28.2725", "U", "en", "Presto"}},
+			},
+		},
+		{
+			name:      "leading comment",
+			userAgent: "(compatible; Bot) Crawler/2.0",
+			products:  []Product{{Comments: []string{"compatible", "Bot"}}, {Name: "Crawler", Version: "2.0"}},
+		},
+		{
+			name:      "empty fields",
+			userAgent: "App/1 (; ;a;)",
+			products:  []Product{{Name: "App", Version: "1", Comments: []string{"a"}}},
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			products:  nil,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Tokenize(tc.userAgent); !reflect.DeepEqual(got, tc.products) {
+				t.Errorf("expected %#v, but got %#v", tc.products, got)
+			}
+		})
+	}
+}
+
+func TestProductString(t *testing.T) {
+	testCases := []struct {
+		product Product
+		want    string
+	}{
+		{Product{Name: "Chrome", Version: "120.0.0.0"}, "Chrome/120.0.0.0"},
+		{Product{Name: "Mozilla", Version: "5.0", Comments: []string{"X11", "Linux x86_64"}}, "Mozilla/5.0 (X11; Linux x86_64)"},
+		{Product{Name: "Tor"}, "Tor"},
+		{Product{Comments: []string{"compatible"}}, "(compatible)"},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		if got := tc.product.String(); got != tc.want {
+			t.Errorf("expected %q, but got %q", tc.want, got)
+		}
+	}
+}
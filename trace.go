@@ -0,0 +1,180 @@
+package useragent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Trace explains how a user agent was classified: which rule of each table
+// matched, where, and which higher priority rules were tried before it.
+type Trace struct {
+	// Browser is the rule of the browsers table, which holds browsers and
+	// bots.
+	Browser RuleTrace
+	// Automation is the rule of the headless browser and automation tool
+	// table. It is skipped when the browser rule already identified a bot.
+	Automation RuleTrace
+	// Device is the rule of the devices table, which decides the operating
+	// system.
+	Device RuleTrace
+	// Tablet and Mobile are the checks that decide the device type. A tablet
+	// match wins over a mobile match.
+	Tablet RuleTrace
+	Mobile RuleTrace
+	// AndroidTablet is true if the user agent is an Android browser without
+	// a mobile token, which makes it a tablet.
+	AndroidTablet bool
+	// Generic is true if no browser or automation rule matched and the
+	// browser was taken from the first product of the user agent.
+	Generic bool
+}
+
+// RuleTrace is the result of checking a table of rules against a user agent.
+type RuleTrace struct {
+	// Table is the name of the table, e.g. "browsers".
+	Table string
+	// Index is the position of the rule that matched in the table, or -1 if
+	// no rule matched.
+	Index int
+	// Name is the name of the rule that matched.
+	Name string
+	// Pattern is the pattern of the rule that matched: a regular expression,
+	// or the product and comment conditions of a browsers rule.
+	Pattern string
+	// Start and End are the byte offsets of the text the rule matched.
+	Start int
+	End   int
+	// Tried are the names of the higher priority rules that were tried and
+	// did not match, in order. If no rule matched, every rule was tried.
+	Tried []string
+	// Skipped is true if the table was not checked.
+	Skipped bool
+}
+
+// Matched returns true if a rule of the table matched.
+func (r *RuleTrace) Matched() bool {
+	return r.Index >= 0
+}
+
+// String describes the result in a single line.
+func (r *RuleTrace) String() string {
+	if r.Skipped {
+		return r.Table + ": skipped"
+	}
+
+	if !r.Matched() {
+		return fmt.Sprintf("%s: no match, %s tried", r.Table, pluralRules(len(r.Tried)))
+	}
+
+	return fmt.Sprintf("%s: rule %d %q matched bytes %d-%d, %s tried before", r.Table, r.Index, r.Name, r.Start, r.End,
+		pluralRules(len(r.Tried)))
+}
+
+// pluralRules returns "1 rule" or "n rules".
+func pluralRules(n int) string {
+	if n == 1 {
+		return "1 rule"
+	}
+
+	return fmt.Sprintf("%d rules", n)
+}
+
+// String describes the trace with a line per table.
+func (t *Trace) String() string {
+	var b strings.Builder
+
+	for _, r := range []*RuleTrace{&t.Browser, &t.Automation, &t.Device, &t.Tablet, &t.Mobile} {
+		b.WriteString(r.String())
+		b.WriteByte('\n')
+	}
+
+	if t.AndroidTablet {
+		b.WriteString("Android browser without a mobile token: tablet\n")
+	}
+
+	if t.Generic {
+		b.WriteString("no rule matched: generic browser from the first product\n")
+	}
+
+	return b.String()
+}
+
+// ParseWithTrace parses a user agent like Parse and explains which rules
+// matched. Tracing is slower than parsing, use it for debugging.
+func ParseWithTrace(userAgent string) (*UserAgent, *Trace) {
+	trace := &Trace{}
+
+	return parse(userAgent, trace), trace
+}
+
+// Explain parses the user agent again and returns the trace of its
+// classification.
+func (ua *UserAgent) Explain() *Trace {
+	_, trace := ParseWithTrace(ua.userAgent)
+
+	return trace
+}
+
+// traceTable returns the trace of a table where the rule at index matched,
+// with index -1 if no rule matched.
+func traceTable[T any](table string, rules []T, index int, userAgent string,
+	rule func(*T) (string, rulePattern),
+) RuleTrace {
+	trace := RuleTrace{Table: table, Index: index}
+
+	tried := len(rules)
+	if index >= 0 {
+		tried = index
+	}
+
+	for i := range tried {
+		name, _ := rule(&rules[i])
+		trace.Tried = append(trace.Tried, name)
+	}
+
+	if index < 0 {
+		return trace
+	}
+
+	name, pattern := rule(&rules[index])
+	trace.Name = name
+	trace.Pattern = pattern.String()
+
+	if loc := pattern.FindStringIndex(userAgent); loc != nil {
+		trace.Start, trace.End = loc[0], loc[1]
+	}
+
+	return trace
+}
+
+// traceCheck returns the trace of a single regular expression.
+func traceCheck(name string, regex *regexp.Regexp, matched bool, userAgent string) RuleTrace {
+	index := -1
+	if matched {
+		index = 0
+	}
+
+	return traceTable(name, []*regexp.Regexp{regex}, index, userAgent, func(re **regexp.Regexp) (string, rulePattern) {
+		return name, *re
+	})
+}
+
+// rulePattern is the pattern of a rule, a regular expression or a token
+// rule.
+type rulePattern interface {
+	String() string
+	FindStringIndex(s string) []int
+}
+
+func browserRule(bp *browserPattern) (string, rulePattern) {
+	return bp.name, bp.rule
+}
+
+func deviceRule(dp *devicePattern) (string, rulePattern) {
+	return dp.name, dp.regex
+}
+
+func automationRule(ap *automationPattern) (string, rulePattern) {
+	return ap.name, ap.regex
+}
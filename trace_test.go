@@ -0,0 +1,90 @@
+package useragent
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseWithTrace(t *testing.T) {
+	t.Parallel()
+
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	ua, trace := ParseWithTrace(userAgent)
+	if ua.Browser() != Parse(userAgent).Browser() {
+		t.Errorf("expected ParseWithTrace to parse like Parse, but got %q", ua.Browser())
+	}
+
+	if !trace.Browser.Matched() || trace.Browser.Name != "Chrome" || browsers[trace.Browser.Index].name != "Chrome" {
+		t.Fatalf("expected the Chrome rule to match, but got %+v", trace.Browser)
+	}
+
+	if got := userAgent[trace.Browser.Start:trace.Browser.End]; !strings.EqualFold(got, "chrome") {
+		t.Errorf("expected the match to span %q, but got %q", "Chrome", got)
+	}
+
+	if len(trace.Browser.Tried) != trace.Browser.Index || !slices.Contains(trace.Browser.Tried, "Edge") {
+		t.Errorf("expected the rules before Chrome to be tried, but got %v", trace.Browser.Tried)
+	}
+
+	if trace.Device.Name != "Windows 10" || userAgent[trace.Device.Start:trace.Device.End] != "Windows NT 10.0" {
+		t.Errorf("expected the Windows 10 rule to match, but got %+v", trace.Device)
+	}
+
+	if trace.Automation.Skipped || trace.Automation.Matched() || len(trace.Automation.Tried) != len(automationTools) {
+		t.Errorf("expected every automation rule to be tried, but got %+v", trace.Automation)
+	}
+
+	if trace.Tablet.Matched() || trace.Mobile.Matched() || trace.AndroidTablet {
+		t.Errorf("expected no device type checks to match, but got %s", trace)
+	}
+}
+
+func TestParseWithTraceBotRule(t *testing.T) {
+	t.Parallel()
+
+	// The URL in the comment makes the generic bot rule fire
+	userAgent := "MyReader/1.0 (+https://example.com/data)"
+
+	_, trace := ParseWithTrace(userAgent)
+	if trace.Browser.Name != "[Bot] Other" {
+		t.Fatalf("expected the generic bot rule to match, but got %+v", trace.Browser)
+	}
+
+	if got := userAgent[trace.Browser.Start:trace.Browser.End]; got != "https" {
+		t.Errorf("expected the match to span %q, but got %q", "https", got)
+	}
+
+	if trace.Device.Matched() {
+		t.Errorf("expected no device rule to match, but got %+v", trace.Device)
+	}
+
+	if !strings.Contains(trace.String(), `browsers: rule `) || !strings.Contains(trace.String(), "devices: no match,") {
+		t.Errorf("unexpected trace description:\n%s", trace)
+	}
+}
+
+func TestParseWithTraceSkipsAutomation(t *testing.T) {
+	t.Parallel()
+
+	ua := Parse("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+
+	trace := ua.Explain()
+	if !trace.Automation.Skipped || trace.Automation.Tried != nil {
+		t.Errorf("expected the automation table to be skipped, but got %+v", trace.Automation)
+	}
+
+	if trace.Automation.String() != "automationTools: skipped" {
+		t.Errorf("unexpected description %q", trace.Automation.String())
+	}
+}
+
+func TestParseWithTraceAndroidTablet(t *testing.T) {
+	t.Parallel()
+
+	_, trace := ParseWithTrace("Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	if !trace.AndroidTablet || trace.Tablet.Matched() {
+		t.Errorf("expected an Android tablet without a tablet token, but got %s", trace)
+	}
+}
@@ -0,0 +1,218 @@
+package useragent
+
+// DeviceType is the form factor of the device a user agent runs on.
+type DeviceType string
+
+// Device types reported by TypedDeviceType.
+const (
+	DeviceTypeDesktop DeviceType = "desktop"
+	DeviceTypeMobile  DeviceType = "mobile"
+	DeviceTypeTablet  DeviceType = "tablet"
+)
+
+// String returns the device type as a string.
+func (d DeviceType) String() string {
+	return string(d)
+}
+
+// AllDeviceTypes returns every device type the parser can report.
+func AllDeviceTypes() []DeviceType {
+	return []DeviceType{
+		DeviceTypeDesktop,
+		DeviceTypeMobile,
+		DeviceTypeTablet,
+	}
+}
+
+// OSFamily is the operating system family of a user agent.
+type OSFamily string
+
+// Operating system families reported by OSFamily.
+const (
+	OSWindows    OSFamily = "windows"
+	OSLinux      OSFamily = "linux"
+	OSMacOS      OSFamily = "macos"
+	OSAndroid    OSFamily = "android"
+	OSIOS        OSFamily = "ios"
+	OSUbuntu     OSFamily = "ubuntu"
+	OSSuse       OSFamily = "suse"
+	OSRedhat     OSFamily = "redhat"
+	OSFedora     OSFamily = "fedora"
+	OSCentOS     OSFamily = "centos"
+	OSChromeOS   OSFamily = "chromeos"
+	OSBlackBerry OSFamily = "blackberry"
+	OSQNX        OSFamily = "qnx"
+	OSBeOS       OSFamily = "beos"
+	OSOS2        OSFamily = "os2"
+	OSBot        OSFamily = "bot"
+	OSUnknown    OSFamily = "unknown"
+)
+
+// String returns the operating system family as a string.
+func (o OSFamily) String() string {
+	return string(o)
+}
+
+// AllOSFamilies returns every operating system family the parser can report.
+func AllOSFamilies() []OSFamily {
+	return []OSFamily{
+		OSWindows,
+		OSLinux,
+		OSMacOS,
+		OSAndroid,
+		OSIOS,
+		OSUbuntu,
+		OSSuse,
+		OSRedhat,
+		OSFedora,
+		OSCentOS,
+		OSChromeOS,
+		OSBlackBerry,
+		OSQNX,
+		OSBeOS,
+		OSOS2,
+		OSBot,
+		OSUnknown,
+	}
+}
+
+// BrowserFamily is the browser family of a user agent. Bots are reported as
+// BrowserBot; use Browser for the name of the bot.
+type BrowserFamily string
+
+// Browser families reported by BrowserFamily.
+const (
+	BrowserDuckDuckGo       BrowserFamily = "DuckDuckGo"
+	BrowserBrave            BrowserFamily = "Brave"
+	BrowserSamsungInternet  BrowserFamily = "Samsung Internet"
+	BrowserUCBrowser        BrowserFamily = "UC Browser"
+	BrowserOperaMini        BrowserFamily = "Opera Mini"
+	BrowserOperaMobile      BrowserFamily = "Opera Mobile"
+	BrowserYandex           BrowserFamily = "Yandex"
+	Browser360Safe          BrowserFamily = "360 Safe"
+	BrowserVivaldi          BrowserFamily = "Vivaldi"
+	BrowserArc              BrowserFamily = "Arc"
+	BrowserOperaGX          BrowserFamily = "Opera GX"
+	BrowserTor              BrowserFamily = "Tor Browser"
+	BrowserLynx             BrowserFamily = "Lynx"
+	BrowserSeaMonkey        BrowserFamily = "SeaMonkey"
+	BrowserPaleMoon         BrowserFamily = "Pale Moon"
+	BrowserMidori           BrowserFamily = "Midori"
+	BrowserAvast            BrowserFamily = "Avast Secure Browser"
+	BrowserOpera            BrowserFamily = "Opera"
+	BrowserEdge             BrowserFamily = "Edge"
+	BrowserChrome           BrowserFamily = "Chrome"
+	BrowserSafari           BrowserFamily = "Safari"
+	BrowserFirefox          BrowserFamily = "Firefox"
+	BrowserInternetExplorer BrowserFamily = "Internet Explorer"
+	BrowserBot              BrowserFamily = "bot"
+	BrowserUnknown          BrowserFamily = "unknown"
+)
+
+// String returns the browser family as a string.
+func (b BrowserFamily) String() string {
+	return string(b)
+}
+
+// AllBrowserFamilies returns every browser family the parser can report.
+func AllBrowserFamilies() []BrowserFamily {
+	return []BrowserFamily{
+		BrowserDuckDuckGo,
+		BrowserBrave,
+		BrowserSamsungInternet,
+		BrowserUCBrowser,
+		BrowserOperaMini,
+		BrowserOperaMobile,
+		BrowserYandex,
+		Browser360Safe,
+		BrowserVivaldi,
+		BrowserArc,
+		BrowserOperaGX,
+		BrowserTor,
+		BrowserLynx,
+		BrowserSeaMonkey,
+		BrowserPaleMoon,
+		BrowserMidori,
+		BrowserAvast,
+		BrowserOpera,
+		BrowserEdge,
+		BrowserChrome,
+		BrowserSafari,
+		BrowserFirefox,
+		BrowserInternetExplorer,
+		BrowserBot,
+		BrowserUnknown,
+	}
+}
+
+// BotCategory is the kind of bot a user agent belongs to.
+type BotCategory string
+
+// Bot categories reported by BotCategory.
+const (
+	BotNone         BotCategory = "none"
+	BotSearchEngine BotCategory = "search_engine"
+	BotAI           BotCategory = "ai"
+	BotSocial       BotCategory = "social"
+	BotCrawler      BotCategory = "crawler"
+	BotSEO          BotCategory = "seo"
+	BotMonitoring   BotCategory = "monitoring"
+	BotTool         BotCategory = "tool"
+	BotScanner      BotCategory = "scanner"
+	BotAutomation   BotCategory = "automation"
+	BotOther        BotCategory = "other"
+)
+
+// String returns the bot category as a string.
+func (c BotCategory) String() string {
+	return string(c)
+}
+
+// AllBotCategories returns every bot category the parser can report.
+func AllBotCategories() []BotCategory {
+	return []BotCategory{
+		BotNone,
+		BotSearchEngine,
+		BotAI,
+		BotSocial,
+		BotCrawler,
+		BotSEO,
+		BotMonitoring,
+		BotTool,
+		BotScanner,
+		BotAutomation,
+		BotOther,
+	}
+}
+
+// Engine is the rendering engine of a browser.
+type Engine string
+
+// Engines reported by Engine.
+const (
+	EngineBlink    Engine = "Blink"
+	EngineWebKit   Engine = "WebKit"
+	EngineGecko    Engine = "Gecko"
+	EngineTrident  Engine = "Trident"
+	EngineEdgeHTML Engine = "EdgeHTML"
+	EnginePresto   Engine = "Presto"
+	EngineUnknown  Engine = "unknown"
+)
+
+// String returns the engine as a string.
+func (e Engine) String() string {
+	return string(e)
+}
+
+// AllEngines returns every engine the parser can report.
+func AllEngines() []Engine {
+	return []Engine{
+		EngineBlink,
+		EngineWebKit,
+		EngineGecko,
+		EngineTrident,
+		EngineEdgeHTML,
+		EnginePresto,
+		EngineUnknown,
+	}
+}
@@ -0,0 +1,109 @@
+package useragent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTypedAccessors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		userAgent  string
+		deviceType DeviceType
+		browser    BrowserFamily
+		os         OSFamily
+	}{
+		{
+			name:       "Chrome on Windows 10",
+			userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			deviceType: DeviceTypeDesktop,
+			browser:    BrowserChrome,
+			os:         OSWindows,
+		},
+		{
+			name:       "Safari on iPhone",
+			userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			deviceType: DeviceTypeMobile,
+			browser:    BrowserSafari,
+			os:         OSIOS,
+		},
+		{
+			name:       "iPad with Safari",
+			userAgent:  "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			deviceType: DeviceTypeTablet,
+			browser:    BrowserSafari,
+			os:         OSIOS,
+		},
+		{
+			name:       "Googlebot",
+			userAgent:  "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			deviceType: DeviceTypeDesktop,
+			browser:    BrowserBot,
+			os:         OSBot,
+		},
+		{
+			name:       "Empty string",
+			userAgent:  "",
+			deviceType: DeviceTypeDesktop,
+			browser:    BrowserUnknown,
+			os:         OSUnknown,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.TypedDeviceType() != tc.deviceType {
+				t.Errorf("expected device type %q, but got %q", tc.deviceType, ua.TypedDeviceType())
+			}
+
+			if ua.BrowserFamily() != tc.browser {
+				t.Errorf("expected browser family %q, but got %q", tc.browser, ua.BrowserFamily())
+			}
+
+			if ua.OSFamily() != tc.os {
+				t.Errorf("expected OS family %q, but got %q", tc.os, ua.OSFamily())
+			}
+
+			if ua.DeviceType() != tc.deviceType.String() {
+				t.Errorf("expected DeviceType() %q, but got %q", tc.deviceType, ua.DeviceType())
+			}
+
+			if ua.OperatingSystem() != tc.os.String() {
+				t.Errorf("expected OperatingSystem() %q, but got %q", tc.os, ua.OperatingSystem())
+			}
+		})
+	}
+}
+
+func TestEnumerationsCoverTables(t *testing.T) {
+	t.Parallel()
+
+	families := AllBrowserFamilies()
+	categories := AllBotCategories()
+
+	for i := range browsers {
+		if !slices.Contains(families, browsers[i].family) {
+			t.Errorf("browser rule %q has family %q missing from AllBrowserFamilies", browsers[i].name, browsers[i].family)
+		}
+
+		if !slices.Contains(categories, browsers[i].category) {
+			t.Errorf("browser rule %q has bot category %q missing from AllBotCategories", browsers[i].name, browsers[i].category)
+		}
+	}
+
+	osFamilies := AllOSFamilies()
+	for i := range devices {
+		if !slices.Contains(osFamilies, devices[i].os) {
+			t.Errorf("device rule %q has OS %q missing from AllOSFamilies", devices[i].name, devices[i].os)
+		}
+	}
+
+	if len(AllDeviceTypes()) != 3 {
+		t.Errorf("expected 3 device types, but got %d", len(AllDeviceTypes()))
+	}
+}
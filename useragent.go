@@ -2,45 +2,89 @@ package useragent
 
 import (
 	"regexp"
+	"strings"
 )
 
 // UserAgent represents a parsed user agent string.
 type UserAgent struct {
 	userAgent            string
-	deviceType           string
+	deviceType           DeviceType
 	browser              string
-	operatingSystem      string
+	browserFamily        BrowserFamily
+	browserVersion       Version
+	botCategory          BotCategory
+	robotsToken          string
+	scanner              Scanner
+	automation           string
+	headless             bool
+	generic              bool
+	confidence           FieldConfidence
+	operatingSystem      OSFamily
+	osVersion            Version
+	engine               Engine
+	engineVersion        Version
 	device               string
+	deviceModel          string
 	browserCheck         bool // check if the browser is valid
 	operatingSystemCheck bool // check if the operating system is valid
 	deviceCheck          bool // check if the device is valid
 }
 
-// browserPattern holds a pre-compiled regex for matching a browser or bot.
+// browserPattern holds a token rule for matching a browser or bot.
 type browserPattern struct {
-	name  string
-	regex *regexp.Regexp
-	isBot bool
+	name     string
+	rule     tokenRule
+	family   BrowserFamily
+	category BotCategory
+	tokens   []string // robots.txt product tokens of a bot
+	// scanner is the name of a security scanner, and scannerVersion reads
+	// its version.
+	scanner        string
+	scannerVersion *regexp.Regexp
 }
 
 // devicePattern holds a pre-compiled regex for matching a device/OS.
 type devicePattern struct {
 	name  string
 	regex *regexp.Regexp
-	os    string
+	os    OSFamily
 }
 
 // Parse parses a user agent string and returns a UserAgent.
 func Parse(userAgent string) *UserAgent {
+	return parse(userAgent, nil)
+}
+
+// parse parses a user agent string, recording the rules that matched in
+// trace if it is not nil.
+func parse(userAgent string, trace *Trace) *UserAgent {
 	// Get the browser
 	browser := "unknown"
+	browserFamily := BrowserUnknown
+	botCategory := BotNone
+	robotsToken := ""
+	scanner := Scanner{}
 	browserCheck := true
+	browserIndex := -1
+	tokens := tokenize(userAgent)
+
+	var browserLoc []int
 
 	for i := range browsers {
 		bp := &browsers[i]
-		if bp.regex.MatchString(userAgent) {
+		if browserLoc = bp.rule.match(&tokens); browserLoc != nil {
+			browserIndex = i
 			browser = bp.name
-			if bp.isBot {
+			browserFamily = bp.family
+			botCategory = bp.category
+
+			if len(bp.tokens) > 0 {
+				robotsToken = bp.tokens[0]
+			}
+
+			scanner = newScanner(bp, userAgent)
+
+			if botCategory != BotNone {
 				browserCheck = false
 			}
 
@@ -48,13 +92,48 @@ func Parse(userAgent string) *UserAgent {
 		}
 	}
 
+	// Check for headless browsers and automation tools
+	browserCategory := botCategory
+	automation := ""
+	headless := false
+	automationIndex := -1
+	automationChecked := botCategory == BotNone || botCategory == BotOther
+
+	if automationChecked {
+		for i := range automationTools {
+			ap := &automationTools[i]
+			if ap.regex.MatchString(userAgent) {
+				automationIndex = i
+				automation = ap.name
+				headless = ap.headless
+				botCategory = BotAutomation
+				browserCheck = false
+
+				break
+			}
+		}
+	}
+
+	// Fall back to the first product for clients no rule recognizes
+	var genericProductToken *productToken
+	if browserIndex < 0 && automationIndex < 0 {
+		genericProductToken = genericProduct(&tokens)
+	}
+
+	generic := genericProductToken != nil
+	if generic {
+		browser = genericProductToken.name
+	}
+
 	// Get the device
 	device := "unknown"
-	operatingSystem := "unknown"
+	operatingSystem := OSUnknown
+	deviceIndex := -1
 
 	for i := range devices {
 		dp := &devices[i]
 		if dp.regex.MatchString(userAgent) {
+			deviceIndex = i
 			device = dp.name
 			operatingSystem = dp.os
 
@@ -62,15 +141,53 @@ func Parse(userAgent string) *UserAgent {
 		}
 	}
 
+	// Get the versions
+	var browserVersion, osVersion Version
+	if re, ok := browserVersionRegEx[browserFamily]; ok {
+		browserVersion = parseVersion(firstSubmatch(re, userAgent))
+	}
+
+	// Presto Opera froze its product token at Opera/9.80 and sends the real
+	// version in a Version token
+	if browserFamily == BrowserOpera && strings.Contains(userAgent, "Opera/9.80") {
+		if version := firstSubmatch(browserVersionRegEx[BrowserSafari], userAgent); version != "" {
+			browserVersion = parseVersion(version)
+		}
+	}
+
+	if re, ok := osVersionRegEx[operatingSystem]; ok {
+		osVersion = parseVersion(strings.ReplaceAll(firstSubmatch(re, userAgent), "_", "."))
+	}
+
+	genericOS := false
+
+	if generic {
+		browserVersion = parseVersion(genericProductToken.version)
+
+		// Mine the comments for the platform the rules did not find
+		if os, version := genericPlatform(&tokens); os != OSUnknown {
+			if operatingSystem == OSUnknown {
+				operatingSystem = os
+				genericOS = true
+			}
+
+			if operatingSystem == os && osVersion.IsZero() {
+				osVersion = version
+			}
+		}
+	}
+
+	engine, engineVersion := detectEngine(userAgent, operatingSystem)
+
 	// Check for bot indicators
 	operatingSystemCheck := true
 	deviceCheck := true
 
-	if operatingSystem == "bot" || operatingSystem == "unknown" {
+	if operatingSystem == OSBot || operatingSystem == OSUnknown {
 		operatingSystemCheck = false
 	}
 
-	if browser == "unknown" {
+	if browser == "unknown" || generic {
 		browserCheck = false
 	}
 
@@ -78,25 +195,80 @@ func Parse(userAgent string) *UserAgent {
 		deviceCheck = false
 	}
 
-	// Get the device type
-	deviceType := "desktop"
-	if tabletCheckRegEx.MatchString(userAgent) {
-		deviceType = "tablet"
-	} else if mobileCheckRegEx.MatchString(userAgent) {
-		deviceType = "mobile"
+	// Get the device model
+	deviceModel := ""
+	if operatingSystem == OSIOS {
+		deviceModel = device
+	} else if operatingSystem == OSAndroid {
+		deviceModel = androidModel(userAgent)
+	}
+
+	// Get the device type, Android tablets are the Android browsers without
+	// a mobile token. Apps and HTTP libraries such as Dalvik and okhttp
+	// never send one, so they are left to the mobile check
+	tablet := tabletCheckRegEx.MatchString(userAgent)
+	androidTablet := operatingSystem == OSAndroid && androidBrowserRegEx.MatchString(userAgent) &&
+		!androidPhoneRegEx.MatchString(userAgent)
+	mobile := mobileCheckRegEx.MatchString(userAgent)
+
+	deviceType := DeviceTypeDesktop
+	if tablet || androidTablet {
+		deviceType = DeviceTypeTablet
+	} else if mobile {
+		deviceType = DeviceTypeMobile
+	}
+
+	if trace != nil {
+		trace.Browser = traceTable("browsers", browsers[:], browserIndex, userAgent, browserRule)
+		trace.Device = traceTable("devices", devices[:], deviceIndex, userAgent, deviceRule)
+		trace.Automation = RuleTrace{Table: "automationTools", Index: -1, Skipped: true}
+		if automationChecked {
+			trace.Automation = traceTable("automationTools", automationTools[:], automationIndex, userAgent, automationRule)
+		}
+		trace.Tablet = traceCheck("tabletCheckRegEx", tabletCheckRegEx, tablet, userAgent)
+		trace.Mobile = traceCheck("mobileCheckRegEx", mobileCheckRegEx, mobile, userAgent)
+		trace.AndroidTablet = androidTablet
+		trace.Generic = generic
 	}
 
 	// Return object
-	return &UserAgent{
+	ua := &UserAgent{
 		userAgent:            userAgent,
 		deviceType:           deviceType,
 		browser:              browser,
+		browserFamily:        browserFamily,
+		browserVersion:       browserVersion,
+		botCategory:          botCategory,
+		robotsToken:          robotsToken,
+		scanner:              scanner,
+		automation:           automation,
+		headless:             headless,
+		generic:              generic,
 		device:               device,
+		deviceModel:          deviceModel,
 		operatingSystem:      operatingSystem,
+		osVersion:            osVersion,
+		engine:               engine,
+		engineVersion:        engineVersion,
 		browserCheck:         browserCheck,
 		operatingSystemCheck: operatingSystemCheck,
 		deviceCheck:          deviceCheck,
 	}
+
+	detected := detectedConfidence{
+		browserMatched:    browserIndex >= 0,
+		browserCategory:   browserCategory,
+		automationMatched: automationIndex >= 0,
+		generic:           generic,
+		genericPlatform:   genericOS,
+		deviceMatched:     deviceIndex >= 0,
+		tablet:            tablet,
+		androidTablet:     androidTablet,
+		mobile:            mobile,
+	}
+	ua.confidence = detected.fieldConfidence(ua)
+
+	return ua
 }
 
 // UserAgent returns the user agent string.
@@ -106,6 +278,11 @@ func (ua *UserAgent) UserAgent() string {
 
 // DeviceType returns the device type of the user agent.
 func (ua *UserAgent) DeviceType() string {
+	return ua.deviceType.String()
+}
+
+// TypedDeviceType returns the device type of the user agent as a DeviceType.
+func (ua *UserAgent) TypedDeviceType() DeviceType {
 	return ua.deviceType
 }
 
@@ -114,16 +291,63 @@ func (ua *UserAgent) Browser() string {
 	return ua.browser
 }
 
+// BrowserFamily returns the browser family of the user agent.
+// Bots are reported as BrowserBot.
+func (ua *UserAgent) BrowserFamily() BrowserFamily {
+	return ua.browserFamily
+}
+
+// BrowserVersion returns the version of the browser, or the zero Version if
+// no version could be found.
+func (ua *UserAgent) BrowserVersion() Version {
+	return ua.browserVersion
+}
+
+// Engine returns the rendering engine of the browser. Every browser on iOS
+// uses WebKit.
+func (ua *UserAgent) Engine() Engine {
+	return ua.engine
+}
+
+// EngineVersion returns the version of the rendering engine, or the zero
+// Version if no version could be found. Blink reports the Chrome version.
+func (ua *UserAgent) EngineVersion() Version {
+	return ua.engineVersion
+}
+
 // Device returns the device of the user agent.
 func (ua *UserAgent) Device() string {
 	return ua.device
 }
 
+// DeviceModel returns the model of the device, such as "Pixel 8" or "iPhone",
+// or an empty string if the user agent does not name one.
+func (ua *UserAgent) DeviceModel() string {
+	return ua.deviceModel
+}
+
 // OperatingSystem returns the operating system of the user agent.
 func (ua *UserAgent) OperatingSystem() string {
+	return ua.operatingSystem.String()
+}
+
+// OSFamily returns the operating system of the user agent as an OSFamily.
+func (ua *UserAgent) OSFamily() OSFamily {
 	return ua.operatingSystem
 }
 
+// OSVersion returns the version of the operating system, or the zero Version
+// if no version could be found. Windows reports the NT version, e.g. "10.0".
+func (ua *UserAgent) OSVersion() Version {
+	return ua.osVersion
+}
+
+// BotCategory returns the kind of bot the user agent belongs to, or BotNone
+// if it is not a bot.
+func (ua *UserAgent) BotCategory() BotCategory {
+	return ua.botCategory
+}
+
 // IsBot returns true if the user agent is a bot.
 // If includeBrowser is true, the browser is also checked.
 func (ua *UserAgent) IsBot(includeBrowser bool) bool {
@@ -152,53 +376,147 @@ func (ua *UserAgent) IsDeviceValid() bool {
 
 // IsMobile returns true if the user agent is a mobile device.
 func (ua *UserAgent) IsMobile() bool {
-	return ua.deviceType == "mobile"
+	return ua.deviceType == DeviceTypeMobile
 }
 
 // IsTablet returns true if the user agent is a tablet device.
 func (ua *UserAgent) IsTablet() bool {
-	return ua.deviceType == "tablet"
+	return ua.deviceType == DeviceTypeTablet
 }
 
 // IsDesktop returns true if the user agent is a desktop device.
 func (ua *UserAgent) IsDesktop() bool {
-	return ua.deviceType == "desktop"
+	return ua.deviceType == DeviceTypeDesktop
 }
 
 // IsWindows returns true if the user agent is a Windows device.
 func (ua *UserAgent) IsWindows() bool {
-	return ua.operatingSystem == "windows"
+	return ua.operatingSystem == OSWindows
 }
 
 // IsLinux returns true if the user agent is a Linux device.
 func (ua *UserAgent) IsLinux() bool {
-	return ua.operatingSystem == "linux"
+	return ua.operatingSystem == OSLinux
 }
 
 // IsMacOS returns true if the user agent is a MacOS device.
 func (ua *UserAgent) IsMacOS() bool {
-	return ua.operatingSystem == "macos"
+	return ua.operatingSystem == OSMacOS
 }
 
 // IsAndroid returns true if the user agent is an Android device.
 func (ua *UserAgent) IsAndroid() bool {
-	return ua.operatingSystem == "android"
+	return ua.operatingSystem == OSAndroid
 }
 
 // IsIOS returns true if the user agent is an iOS device.
 func (ua *UserAgent) IsIOS() bool {
-	return ua.operatingSystem == "ios"
+	return ua.operatingSystem == OSIOS
+}
+
+// firstSubmatch returns the first non-empty capture group of re in s.
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	matches := re.FindStringSubmatch(s)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	for _, m := range matches[1:] {
+		if m != "" {
+			return m
+		}
+	}
+
+	return ""
 }
 
-func compileBrowser(name, pattern string, isBot bool) browserPattern {
+// detectEngine returns the rendering engine and its version. Chrome 27 and
+// older used WebKit, and every browser on iOS uses WebKit.
+func detectEngine(userAgent string, operatingSystem OSFamily) (Engine, Version) {
+	webKitVersion := firstSubmatch(webKitVersionRegEx, userAgent)
+
+	if operatingSystem == OSIOS && webKitVersion != "" {
+		return EngineWebKit, parseVersion(webKitVersion)
+	}
+
+	if version := firstSubmatch(prestoVersionRegEx, userAgent); version != "" {
+		return EnginePresto, parseVersion(version)
+	}
+
+	if tridentRegEx.MatchString(userAgent) {
+		return EngineTrident, parseVersion(firstSubmatch(tridentVersionRegEx, userAgent))
+	}
+
+	if webKitVersion != "" {
+		if version := firstSubmatch(edgeHTMLVersionRegEx, userAgent); version != "" {
+			return EngineEdgeHTML, parseVersion(version)
+		}
+
+		if version := firstSubmatch(browserVersionRegEx[BrowserChrome], userAgent); majorVersion(version) >= 28 {
+			return EngineBlink, parseVersion(version)
+		}
+
+		return EngineWebKit, parseVersion(webKitVersion)
+	}
+
+	if version := firstSubmatch(geckoVersionRegEx, userAgent); version != "" {
+		return EngineGecko, parseVersion(version)
+	}
+
+	return EngineUnknown, Version{}
+}
+
+// androidModel returns the device model from the Android comment, skipping
+// the security level, locale and form factor fields some browsers add.
+func androidModel(userAgent string) string {
+	comment := firstSubmatch(androidCommentRegEx, userAgent)
+	if comment == "" {
+		return ""
+	}
+
+	fields := strings.Split(comment, ";")
+	for i, field := range fields {
+		if !androidVersionRegEx.MatchString(field) {
+			continue
+		}
+
+		for _, model := range fields[i+1:] {
+			model = strings.TrimSpace(model)
+			if model == "" || androidNonModelRegEx.MatchString(model) {
+				continue
+			}
+
+			model, _, _ = strings.Cut(model, " Build/")
+
+			return model
+		}
+
+		break
+	}
+
+	return ""
+}
+
+func compileBrowser(name string, rule tokenRule) browserPattern {
 	return browserPattern{
-		name:  name,
-		regex: regexp.MustCompile(`(?i)` + pattern),
-		isBot: isBot,
+		name:     name,
+		rule:     rule,
+		family:   BrowserFamily(name),
+		category: BotNone,
 	}
 }
 
-func compileDevice(name, pattern, os string) devicePattern {
+func compileBot(name string, rule tokenRule, category BotCategory, tokens ...string) browserPattern {
+	return browserPattern{
+		name:     name,
+		rule:     rule,
+		family:   BrowserBot,
+		category: category,
+		tokens:   tokens,
+	}
+}
+
+func compileDevice(name, pattern string, os OSFamily) devicePattern {
 	return devicePattern{
 		name:  name,
 		regex: regexp.MustCompile(`(?i)` + pattern),
@@ -211,114 +529,206 @@ var (
 		`(?i)Mobile|iP(hone|od|ad)|Android|BlackBerry|IEMobile|Kindle|NetFront|` +
 			`Silk-Accelerated|(hpw|web)OS|Fennec|Minimo|Opera M(obi|ini)|Blazer|Dolfin|Dolphin|Skyfire|Zune`,
 	)
-	tabletCheckRegEx = regexp.MustCompile(`(?i)(tablet|ipad|playbook)|.*mobile.*android.*`)
+	tabletCheckRegEx  = regexp.MustCompile(`(?i)(tablet|ipad|playbook)|.*mobile.*android.*`)
+	androidPhoneRegEx = regexp.MustCompile(`(?i)mobile|opera mini`)
+	// androidBrowserRegEx matches the engine tokens of Android browsers.
+	androidBrowserRegEx = regexp.MustCompile(`(?i)applewebkit|chrome/`)
+
+	webKitVersionRegEx   = regexp.MustCompile(`(?i)applewebkit/([\d.]+)`)
+	prestoVersionRegEx   = regexp.MustCompile(`(?i)presto/([\d.]+)`)
+	tridentRegEx         = regexp.MustCompile(`(?i)trident/|msie `)
+	tridentVersionRegEx  = regexp.MustCompile(`(?i)trident/([\d.]+)`)
+	edgeHTMLVersionRegEx = regexp.MustCompile(`(?i)edge/([\d.]+)`)
+	geckoVersionRegEx    = regexp.MustCompile(`(?i)rv:([\d.]+)\)\s*gecko/`)
+
+	androidCommentRegEx  = regexp.MustCompile(`(?i)\(([^()]*android[^()]*)\)`)
+	androidVersionRegEx  = regexp.MustCompile(`(?i)^\s*android(\s+[\d.]+)?\s*$`)
+	androidNonModelRegEx = regexp.MustCompile(`(?i)^(u|i|n|mobile|tablet|wv|linux|[a-z]{2}([-_][a-z]{2})?|rv:.*)$`)
+
+	browserVersionRegEx = map[BrowserFamily]*regexp.Regexp{
+		BrowserDuckDuckGo:       regexp.MustCompile(`(?i)ddg/([\d.]+)`),
+		BrowserBrave:            regexp.MustCompile(`(?i)(?:chrome|crios)/([\d.]+)`),
+		BrowserSamsungInternet:  regexp.MustCompile(`(?i)samsungbrowser/([\d.]+)`),
+		BrowserUCBrowser:        regexp.MustCompile(`(?i)ucbrowser/([\d.]+)`),
+		BrowserOperaMini:        regexp.MustCompile(`(?i)opera mini/([\d.]+)`),
+		BrowserOperaMobile:      regexp.MustCompile(`(?i)version/([\d.]+)|opera mobi/([\d.]+)`),
+		BrowserYandex:           regexp.MustCompile(`(?i)yabrowser/([\d.]+)`),
+		Browser360Safe:          regexp.MustCompile(`(?i)chrome/([\d.]+)`),
+		BrowserVivaldi:          regexp.MustCompile(`(?i)vivaldi/([\d.]+)`),
+		BrowserArc:              regexp.MustCompile(`(?i)arc/([\d.]+)`),
+		BrowserOperaGX:          regexp.MustCompile(`(?i)(?:oprgx|opr)/([\d.]+)`),
+		BrowserTor:              regexp.MustCompile(`(?i)firefox/([\d.]+)`),
+		BrowserLynx:             regexp.MustCompile(`(?i)lynx/([\d.]+)`),
+		BrowserSeaMonkey:        regexp.MustCompile(`(?i)seamonkey/([\d.]+)`),
+		BrowserPaleMoon:         regexp.MustCompile(`(?i)palemoon/([\d.]+)`),
+		BrowserMidori:           regexp.MustCompile(`(?i)midori/([\d.]+)`),
+		BrowserAvast:            regexp.MustCompile(`(?i)avast/([\d.]+)`),
+		BrowserOpera:            regexp.MustCompile(`(?i)opr/([\d.]+)|version/([\d.]+)|opera[/ ]([\d.]+)`),
+		BrowserEdge:             regexp.MustCompile(`(?i)(?:edge|edga|edgios|edg)/([\d.]+)`),
+		BrowserChrome:           regexp.MustCompile(`(?i)(?:chrome|crios)/([\d.]+)`),
+		BrowserSafari:           regexp.MustCompile(`(?i)version/([\d.]+)`),
+		BrowserFirefox:          regexp.MustCompile(`(?i)(?:firefox|fxios)/([\d.]+)`),
+		BrowserInternetExplorer: regexp.MustCompile(`(?i)msie ([\d.]+)|trident/7.*rv:([\d.]+)`),
+	}
+
+	osVersionRegEx = map[OSFamily]*regexp.Regexp{
+		OSWindows:  regexp.MustCompile(`(?i)windows (?:nt|phone) ([\d.]+)`),
+		OSMacOS:    regexp.MustCompile(`(?i)mac os x ([\d_.]+)`),
+		OSIOS:      regexp.MustCompile(`(?i)os ([\d_]+) like mac os x`),
+		OSAndroid:  regexp.MustCompile(`(?i)android ([\d.]+)`),
+		OSChromeOS: regexp.MustCompile(`(?i)cros \S+ ([\d.]+)`),
+	}
 
 	devices = [...]devicePattern{
-		compileDevice("Windows 3.11", `Win16`, "windows"),
-		compileDevice("Windows 95", `(Windows 95)|(Win95)|(Windows_95)`, "windows"),
-		compileDevice("Windows 98", `(Windows 98)|(Win98)`, "windows"),
-		compileDevice("Windows 2000", `(Windows NT 5.0)|(Windows 2000)`, "windows"),
-		compileDevice("Windows XP", `(Windows NT 5.1)|(Windows XP)`, "windows"),
-		compileDevice("Windows Server 2003", `(Windows NT 5.2)`, "windows"),
-		compileDevice("Windows Vista", `(Windows NT 6.0)`, "windows"),
-		compileDevice("Windows 7", `(Windows NT 6.1)`, "windows"),
-		compileDevice("Windows 8", `(Windows NT 6.2)`, "windows"),
-		compileDevice("Windows 10", `(Windows 10.0)|(Windows NT 10.0)`, "windows"),
-		compileDevice("Windows NT 4.0", `(Windows NT 4.0)|(WinNT4.0)|(WinNT)|(Windows NT)`, "windows"),
-		compileDevice("Windows ME", `Windows ME`, "windows"),
-		compileDevice("Windows Phone", `Windows Phone`, "windows"),
-		compileDevice("Open BSD", `OpenBSD`, "linux"),
-		compileDevice("FreeBSD", `FreeBSD`, "linux"),
-		compileDevice("NetBSD", `NetBSD`, "linux"),
-		compileDevice("Solaris", `Solaris|SunOS`, "linux"),
-		compileDevice("Android", `Android`, "android"),
-		compileDevice("Ubuntu", `Ubuntu`, "ubuntu"),
-		compileDevice("Suse", `Suse`, "suse"),
-		compileDevice("Redhat", `Redhat`, "redhat"),
-		compileDevice("Fedora", `Fedora`, "fedora"),
-		compileDevice("Centos", `Centos`, "centos"),
-		compileDevice("Chrome OS", `CrOS`, "chromeos"),
-		compileDevice("Linux", `(Linux)|(X11)`, "linux"),
-		compileDevice("Mac OS", `(Mac_PowerPC)|(Macintosh)`, "macos"),
-		compileDevice("BlackBerry", `BlackBerry`, "blackberry"),
-		compileDevice("QNX", `QNX`, "qnx"),
-		compileDevice("BeOS", `BeOS`, "beos"),
-		compileDevice("OS/2", `OS/2`, "os2"),
-		compileDevice("iPhone", `iPhone`, "ios"),
-		compileDevice("iPad", `iPad`, "ios"),
-		compileDevice("iPod", `iPod`, "ios"),
+		compileDevice("Windows 3.11", `Win16`, OSWindows),
+		compileDevice("Windows 95", `(Windows 95)|(Win95)|(Windows_95)`, OSWindows),
+		compileDevice("Windows 98", `(Windows 98)|(Win98)`, OSWindows),
+		compileDevice("Windows 2000", `(Windows NT 5.0)|(Windows 2000)`, OSWindows),
+		compileDevice("Windows XP", `(Windows NT 5.1)|(Windows XP)`, OSWindows),
+		compileDevice("Windows Server 2003", `(Windows NT 5.2)`, OSWindows),
+		compileDevice("Windows Vista", `(Windows NT 6.0)`, OSWindows),
+		compileDevice("Windows 7", `(Windows NT 6.1)`, OSWindows),
+		compileDevice("Windows 8", `(Windows NT 6.2)`, OSWindows),
+		compileDevice("Windows 10", `(Windows 10.0)|(Windows NT 10.0)`, OSWindows),
+		compileDevice("Windows NT 4.0", `(Windows NT 4.0)|(WinNT4.0)|(WinNT)|(Windows NT)`, OSWindows),
+		compileDevice("Windows ME", `Windows ME`, OSWindows),
+		compileDevice("Windows Phone", `Windows Phone`, OSWindows),
+		compileDevice("Open BSD", `OpenBSD`, OSLinux),
+		compileDevice("FreeBSD", `FreeBSD`, OSLinux),
+		compileDevice("NetBSD", `NetBSD`, OSLinux),
+		compileDevice("Solaris", `Solaris|SunOS`, OSLinux),
+		compileDevice("Android", `Android`, OSAndroid),
+		compileDevice("Ubuntu", `Ubuntu`, OSUbuntu),
+		compileDevice("Suse", `Suse`, OSSuse),
+		compileDevice("Redhat", `Redhat`, OSRedhat),
+		compileDevice("Fedora", `Fedora`, OSFedora),
+		compileDevice("Centos", `Centos`, OSCentOS),
+		compileDevice("Chrome OS", `CrOS`, OSChromeOS),
+		compileDevice("Linux", `(Linux)|(X11)`, OSLinux),
+		compileDevice("Mac OS", `(Mac_PowerPC)|(Macintosh)`, OSMacOS),
+		compileDevice("BlackBerry", `BlackBerry`, OSBlackBerry),
+		compileDevice("QNX", `QNX`, OSQNX),
+		compileDevice("BeOS", `BeOS`, OSBeOS),
+		compileDevice("OS/2", `OS/2`, OSOS2),
+		compileDevice("iPod", `iPod`, OSIOS),
+		compileDevice("iPhone", `iPhone`, OSIOS),
+		compileDevice("iPad", `iPad`, OSIOS),
 		compileDevice("Search Bot",
 			`(nuhk)|(Googlebot)|(Yammybot)|(Openbot)|(Slurp)|(MSNBot)|(Ask Jeeves/Teoma)`+
 				`|(ia_archiver)|(Baiduspider)|(FacebookExternalHit)|(Twitterbot)|(Riddler)`+
 				`|(LinkedInBot)|(Instagram)|(Pinterest)|(chatgpt)|(openai)|(bingbot)`+
 				`|(duckduckbot)|(yandexbot)|(snapchat)|(discordbot)`+
-				`|(claudebot)|(gptbot)|(perplexitybot)|(bytespider)|(petalbot)|(applebot)|(amazonbot)`,
-			"bot"),
+				`|(claudebot)|(gptbot)|(perplexitybot)|(bytespider)|(petalbot)|(applebot)|(amazonbot)`+
+				`|(ccbot)|(claude-user)|(claude-searchbot)|(perplexity-user)|(oai-searchbot)|(meta-external)`+
+				`|(adsbot-google)|(mediapartners-google)|(storebot-google)|(google-inspectiontool)|(googleother)`+
+				`|(feedfetcher-google)|(apis-google)|(bingpreview)|(adidxbot)|(microsoftpreview)`,
+			OSBot),
 	}
 
 	browsers = [...]browserPattern{
+		// Security scanners, which often send a full browser user agent
+		compileScanner("sqlmap", product("sqlmap"), `sqlmap/([\d.]+)`),
+		compileScanner("Nikto", product("nikto"), `nikto/([\d.]+)`),
+		compileScanner("Nmap Scripting Engine", anyOf(product("nmap"), comment("nmap scripting engine")), `nmap/([\d.]+)`),
+		compileScanner("masscan", product("masscan"), `masscan/([\d.]+)`),
+		compileScanner("ZGrab", product("zgrab"), `zgrab/([\d.]+)`),
+		compileScanner("Nuclei", anyOf(product("nuclei"), comment("nuclei")), `nuclei/v?([\d.]+)`),
+		compileScanner("WPScan", product("wpscan"), `wpscan[ /]v?([\d.]+)`),
+		compileScanner("Acunetix", anyOf(prefix("acunetix"), comment("acunetix")), `acunetix[ /-]v?([\d.]+)`),
+		compileScanner("Burp Suite",
+			anyOf(product("burp", "burpsuite", "burpcollaborator"), comment("burp suite", "burp collaborator")), `burp(?:suite)?/([\d.]+)`),
+		compileScanner("OpenVAS", anyOf(prefix("openvas"), comment("openvas")), `openvas(?:-vt)?[ /]([\d.]+)`),
+		compileScanner("Nessus", anyOf(product("nessus"), comment("nessus")), `nessus[ /]([\d.]+)`),
+		compileScanner("Netsparker", anyOf(product("netsparker"), comment("netsparker")), `netsparker/([\d.]+)`),
+		compileScanner("Arachni", product("arachni"), `arachni/v?([\d.]+)`),
+		compileScanner("w3af", prefix("w3af"), ""),
+		compileScanner("commix", product("commix"), `commix/v?([\d.]+)`),
+		compileScanner("Wfuzz", product("wfuzz"), `wfuzz/([\d.]+)`),
+		compileScanner("DirBuster", prefix("dirbuster"), `dirbuster-([\d.]+)`),
+		compileScanner("gobuster", product("gobuster"), `gobuster/([\d.]+)`),
+		compileScanner("feroxbuster", product("feroxbuster"), `feroxbuster/([\d.]+)`),
+		compileScanner("ZmEu", product("zmeu"), ""),
+		compileScanner("Jorgee", product("jorgee"), ""),
+		// Bots that send a full browser user agent
+		compileBot("[Bot] Bytespider", product("bytespider"), BotAI, "Bytespider"),
+		compileBot("[Bot] Googlebot",
+			anyOf(prefix("googlebot", "googleother", "adsbot-google"),
+				product("mediapartners-google", "storebot-google", "google-inspectiontool", "feedfetcher-google",
+					"apis-google")),
+			BotSearchEngine, "Googlebot"),
+		compileBot("[Bot] Bingbot", product("bingbot", "bingpreview", "adidxbot", "microsoftpreview"), BotSearchEngine,
+			"bingbot"),
 		// Browsers
-		compileBrowser("DuckDuckGo", `ddg`, false),
-		compileBrowser("Brave", `brave`, false),
-		compileBrowser("Samsung Internet", `samsungbrowser`, false),
-		compileBrowser("UC Browser", `ucbrowser`, false),
-		compileBrowser("Opera Mini", `opera mini`, false),
-		compileBrowser("Opera Mobile", `opera mobi`, false),
-		compileBrowser("Yandex", `yabrowser`, false),
-		compileBrowser("360 Safe", `360ee`, false),
-		compileBrowser("Vivaldi", `vivaldi`, false),
-		compileBrowser("Arc", `arc/`, false),
-		compileBrowser("Opera GX", `oprgx`, false),
-		compileBrowser("Tor Browser", `tor`, false),
-		compileBrowser("Lynx", `lynx`, false),
-		compileBrowser("SeaMonkey", `seamonkey`, false),
-		compileBrowser("Pale Moon", `palemoon`, false),
-		compileBrowser("Midori", `midori`, false),
-		compileBrowser("Avast Secure Browser", `avast`, false),
-		compileBrowser("Opera", `(opera)|(opr/)`, false),
-		compileBrowser("Edge", `(edge)|(edg)`, false),
-		compileBrowser("Chrome", `(chrome)|(crios)`, false),
-		compileBrowser("Safari", `safari`, false),
-		compileBrowser("Firefox", `firefox`, false),
-		compileBrowser("Internet Explorer", `(msie)|(trident/7)`, false),
+		compileBrowser("DuckDuckGo", product("ddg")),
+		compileBrowser("Brave", product("brave")),
+		compileBrowser("Samsung Internet", product("samsungbrowser")),
+		compileBrowser("UC Browser", product("ucbrowser")),
+		compileBrowser("Opera Mini", comment("opera mini")),
+		compileBrowser("Opera Mobile", comment("opera mobi")),
+		compileBrowser("Yandex", product("yabrowser")),
+		compileBrowser("360 Safe", product("360ee")),
+		compileBrowser("Vivaldi", product("vivaldi")),
+		compileBrowser("Arc", product("arc")),
+		compileBrowser("Opera GX", product("oprgx")),
+		compileBrowser("Tor Browser", anyOf(product("tor", "torbrowser"), comment("tor"))),
+		compileBrowser("Lynx", product("lynx")),
+		compileBrowser("SeaMonkey", product("seamonkey")),
+		compileBrowser("Pale Moon", product("palemoon")),
+		compileBrowser("Midori", product("midori")),
+		compileBrowser("Avast Secure Browser", product("avast")),
+		compileBrowser("Opera", product("opera", "opr")),
+		compileBrowser("Edge", prefix("edg")),
+		compileBrowser("Chrome", product("chrome", "crios", "headlesschrome")),
+		compileBrowser("Firefox", product("firefox", "fxios")),
+		compileBrowser("Safari", product("safari")),
+		compileBrowser("Internet Explorer", anyOf(comment("msie"), product("trident/7"))),
 		// Search Engines
-		compileBrowser("[Bot] Googlebot", `google`, true),
-		compileBrowser("[Bot] Bingbot", `bing`, true),
-		compileBrowser("[Bot] Yahoo! Slurp", `slurp`, true),
-		compileBrowser("[Bot] DuckDuckBot", `(duckduckgo)|(duckduckbot)`, true),
-		compileBrowser("[Bot] Baidu", `baidu`, true),
-		compileBrowser("[Bot] Yandex", `yandex`, true),
-		compileBrowser("[Bot] Sogou", `sogou`, true),
-		compileBrowser("[Bot] Exabot", `exabot`, true),
-		compileBrowser("[Bot] MSN", `msn`, true),
-		// Chat bots
-		compileBrowser("[Bot] ChatGPT", `chatgpt`, true),
-		compileBrowser("[Bot] ClaudeBot", `claudebot`, true),
-		compileBrowser("[Bot] GPTBot", `gptbot`, true),
-		compileBrowser("[Bot] PerplexityBot", `perplexitybot`, true),
-		compileBrowser("[Bot] OpenAI", `openai`, true),
+		compileBot("[Bot] Googlebot", anyOf(product("google"), prefix("google-")), BotSearchEngine, "Googlebot"),
+		compileBot("[Bot] Bingbot", prefix("bing"), BotSearchEngine, "bingbot"),
+		compileBot("[Bot] Yahoo! Slurp", comment("slurp"), BotSearchEngine, "Slurp"),
+		compileBot("[Bot] DuckDuckBot", prefix("duckduckbot", "duckduckgo"), BotSearchEngine, "DuckDuckBot"),
+		compileBot("[Bot] Baidu", prefix("baidu"), BotSearchEngine, "Baiduspider"),
+		compileBot("[Bot] Yandex", prefix("yandex"), BotSearchEngine, "YandexBot"),
+		compileBot("[Bot] Sogou", prefix("sogou"), BotSearchEngine, "Sogou"),
+		compileBot("[Bot] Exabot", prefix("exabot"), BotSearchEngine, "Exabot"),
+		compileBot("[Bot] MSN", prefix("msnbot"), BotSearchEngine, "msnbot"),
+		// AI crawlers and assistants
+		compileBot("[Bot] OAI-SearchBot", product("oai-searchbot"), BotAI, "OAI-SearchBot"),
+		compileBot("[Bot] ChatGPT", prefix("chatgpt"), BotAI, "ChatGPT-User"),
+		compileBot("[Bot] Claude-User", product("claude-user"), BotAI, "Claude-User"),
+		compileBot("[Bot] Claude-SearchBot", product("claude-searchbot"), BotAI, "Claude-SearchBot"),
+		compileBot("[Bot] ClaudeBot", product("claudebot"), BotAI, "ClaudeBot"),
+		compileBot("[Bot] GPTBot", product("gptbot"), BotAI, "GPTBot"),
+		compileBot("[Bot] Perplexity-User", product("perplexity-user"), BotAI, "Perplexity-User"),
+		compileBot("[Bot] PerplexityBot", product("perplexitybot"), BotAI, "PerplexityBot"),
+		compileBot("[Bot] CCBot", product("ccbot"), BotAI, "CCBot"),
+		compileBot("[Bot] Meta-ExternalAgent", product("meta-externalagent"), BotAI, "meta-externalagent"),
+		compileBot("[Bot] Meta-ExternalFetcher", product("meta-externalfetcher"), BotAI, "meta-externalfetcher"),
+		compileBot("[Bot] OpenAI", comment("openai"), BotAI),
 		// Social Media
-		compileBrowser("[Bot] Facebook", `facebook`, true),
-		compileBrowser("[Bot] Pinterest", `pinterest`, true),
-		compileBrowser("[Bot] LinkedInBot", `linkedin`, true),
-		compileBrowser("[Bot] Instagram", `instagram`, true),
-		compileBrowser("[Bot] Twitterbot", `twitter`, true),
-		compileBrowser("[Bot] Snapchat", `snapchat`, true),
-		compileBrowser("[Bot] Discord", `discord`, true),
+		compileBot("[Bot] Facebook", prefix("facebook"), BotSocial, "facebookexternalhit"),
+		compileBot("[Bot] Pinterest", prefix("pinterest"), BotSocial, "Pinterestbot"),
+		compileBot("[Bot] LinkedInBot", prefix("linkedin"), BotSocial, "LinkedInBot"),
+		compileBot("[Bot] Instagram", product("instagram"), BotSocial),
+		compileBot("[Bot] Twitterbot", prefix("twitter"), BotSocial, "Twitterbot"),
+		compileBot("[Bot] Snapchat", prefix("snapchat"), BotSocial),
+		compileBot("[Bot] Discord", prefix("discord"), BotSocial, "Discordbot"),
 		// Common Tools and Bots
-		compileBrowser("[Bot] Bytespider", `bytespider`, true),
-		compileBrowser("[Bot] PetalBot", `petalbot`, true),
-		compileBrowser("[Bot] Applebot", `applebot`, true),
-		compileBrowser("[Bot] Amazon", `amazonbot`, true),
-		compileBrowser("[Bot] Majestic", `mj12bot`, true),
-		compileBrowser("[Bot] Ahrefs", `ahrefs`, true),
-		compileBrowser("[Bot] SEMRush", `semrush`, true),
-		compileBrowser("[Bot] Moz or OpenSiteExplorer", `(rogerbot)|(dotbot)`, true),
-		compileBrowser("[Bot] Screaming Frog", `(frog)|(screaming)`, true),
-		compileBrowser("[Bot] Pingdom", `pingdom`, true),
-		compileBrowser("[Bot] Riddler", `riddler`, true),
-		compileBrowser("[Bot] W3C Validator", `w3c_validator`, true),
+		compileBot("[Bot] PetalBot", product("petalbot"), BotCrawler, "PetalBot"),
+		compileBot("[Bot] Applebot", prefix("applebot"), BotCrawler, "Applebot"),
+		compileBot("[Bot] Amazon", product("amazonbot"), BotCrawler, "Amazonbot"),
+		compileBot("[Bot] Majestic", product("mj12bot"), BotSEO, "MJ12bot"),
+		compileBot("[Bot] Ahrefs", prefix("ahrefs"), BotSEO, "AhrefsBot"),
+		compileBot("[Bot] SEMRush", prefix("semrush"), BotSEO, "SemrushBot"),
+		compileBot("[Bot] Moz or OpenSiteExplorer", product("rogerbot", "dotbot"), BotSEO, "rogerbot", "dotbot"),
+		// Screaming Frog sends "Screaming Frog SEO Spider/19.0", which is several
+		// products outside of a comment
+		compileBot("[Bot] Screaming Frog", anyOf(product("screaming"), comment("screaming frog")), BotSEO),
+		compileBot("[Bot] Pingdom", prefix("pingdom"), BotMonitoring),
+		compileBot("[Bot] Riddler", prefix("riddler"), BotTool),
+		compileBot("[Bot] W3C Validator", prefix("w3c_validator"), BotTool, "W3C_Validator"),
 		// Check for strings commonly used in bot user agents
-		compileBrowser("[Bot] Other", `(crawler)|(api)|(spider)|(http)|(bot)|(archive)|(info)|(data)`, true),
+		compileBot("[Bot] Other", anyOf(comment("http", "https"), contains("bot", "crawler", "spider", "archiver", "http")),
+			BotOther),
 	}
 )
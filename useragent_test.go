@@ -161,6 +161,24 @@ func TestParse(t *testing.T) {
 			os:         "android",
 			isBot:      false,
 		},
+		{
+			name:       "Firefox on iPhone",
+			userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Mobile/15E148 Safari/605.1.15",
+			deviceType: "mobile",
+			browser:    "Firefox",
+			device:     "iPhone",
+			os:         "ios",
+			isBot:      false,
+		},
+		{
+			name:       "Safari on iPod",
+			userAgent:  "Mozilla/5.0 (iPod touch; CPU iPhone OS 12_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.1 Mobile/15E148 Safari/604.1",
+			deviceType: "mobile",
+			browser:    "Safari",
+			device:     "iPod",
+			os:         "ios",
+			isBot:      false,
+		},
 		// Tablets
 		{
 			name:       "iPad with Safari",
@@ -180,6 +198,24 @@ func TestParse(t *testing.T) {
 			os:         "android",
 			isBot:      false,
 		},
+		{
+			name:       "Android tablet without mobile token",
+			userAgent:  "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Safari/537.36",
+			deviceType: "tablet",
+			browser:    "Chrome",
+			device:     "Android",
+			os:         "android",
+			isBot:      false,
+		},
+		{
+			name:       "Android app on a phone",
+			userAgent:  "Dalvik/2.1.0 (Linux; U; Android 11; SM-G991B Build/RP1A.200720.012)",
+			deviceType: "mobile",
+			browser:    "Dalvik",
+			device:     "Android",
+			os:         "android",
+			isBot:      true,
+		},
 		// Windows versions
 		{
 			name:       "Windows 7",
@@ -551,3 +587,179 @@ func TestWOW64BugFix(t *testing.T) {
 		t.Errorf("expected device %q, but got %q (WOW64 false positive)", "Windows 10", ua.Device())
 	}
 }
+
+func TestVersions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userAgent      string
+		browserVersion string
+		osVersion      string
+	}{
+		{
+			name:           "Chrome on Windows 10",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			browserVersion: "120.0.0.0",
+			osVersion:      "10.0",
+		},
+		{
+			name:           "Safari on macOS",
+			userAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			browserVersion: "17.2",
+			osVersion:      "14.2",
+		},
+		{
+			name:           "Safari on iPhone",
+			userAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			browserVersion: "17.2",
+			osVersion:      "17.2",
+		},
+		{
+			name:           "Chrome on Android",
+			userAgent:      "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			browserVersion: "120.0.6099.144",
+			osVersion:      "14",
+		},
+		{
+			name:           "Edge on Windows 10",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			browserVersion: "120.0.2210.91",
+			osVersion:      "10.0",
+		},
+		{
+			name:           "Internet Explorer 11",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Trident/7.0; rv:11.0) like Gecko",
+			browserVersion: "11.0",
+			osVersion:      "10.0",
+		},
+		{
+			name:           "Chrome OS",
+			userAgent:      "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			browserVersion: "120.0.0.0",
+			osVersion:      "14541.0.0",
+		},
+		{
+			name:           "Presto Opera",
+			userAgent:      "Opera/9.80 (Windows NT 6.1; U; en) Presto/2.12.388 Version/12.16",
+			browserVersion: "12.16",
+			osVersion:      "6.1",
+		},
+		{
+			name:           "Chromium Opera",
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+			browserVersion: "106.0.0.0",
+			osVersion:      "10.0",
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.BrowserVersion().String() != tc.browserVersion {
+				t.Errorf("expected browser version %q, but got %q", tc.browserVersion, ua.BrowserVersion())
+			}
+
+			if ua.OSVersion().String() != tc.osVersion {
+				t.Errorf("expected OS version %q, but got %q", tc.osVersion, ua.OSVersion())
+			}
+		})
+	}
+}
+
+func TestEngines(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userAgent     string
+		engine        Engine
+		engineVersion string
+	}{
+		{
+			name:          "Chrome",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			engine:        EngineBlink,
+			engineVersion: "120.0.0.0",
+		},
+		{
+			name:          "Edge",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			engine:        EngineBlink,
+			engineVersion: "120.0.0.0",
+		},
+		{
+			name:          "Legacy Edge",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19045",
+			engine:        EngineEdgeHTML,
+			engineVersion: "18.19045",
+		},
+		{
+			name:          "Safari",
+			userAgent:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			engine:        EngineWebKit,
+			engineVersion: "605.1.15",
+		},
+		{
+			name:          "Chrome on iPhone",
+			userAgent:     "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			engine:        EngineWebKit,
+			engineVersion: "605.1.15",
+		},
+		{
+			name:          "Old Chrome",
+			userAgent:     "Mozilla/5.0 (Windows NT 6.1) AppleWebKit/534.13 (KHTML, like Gecko) Chrome/9.0.597.0 Safari/534.13",
+			engine:        EngineWebKit,
+			engineVersion: "534.13",
+		},
+		{
+			name:          "Firefox",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			engine:        EngineGecko,
+			engineVersion: "121.0",
+		},
+		{
+			name:          "Internet Explorer 11",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Trident/7.0; rv:11.0) like Gecko",
+			engine:        EngineTrident,
+			engineVersion: "7.0",
+		},
+		{
+			name:      "Internet Explorer 6",
+			userAgent: "Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1)",
+			engine:    EngineTrident,
+		},
+		{
+			name:          "Presto Opera",
+			userAgent:     "Opera/9.80 (Windows NT 6.1; U; en) Presto/2.12.388 Version/12.16",
+			engine:        EnginePresto,
+			engineVersion: "2.12.388",
+		},
+		{
+			name:      "curl",
+			userAgent: "curl/8.4.0",
+			engine:    EngineUnknown,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua := Parse(tc.userAgent)
+			if ua.Engine() != tc.engine {
+				t.Errorf("expected engine %q, but got %q", tc.engine, ua.Engine())
+			}
+
+			if ua.EngineVersion().String() != tc.engineVersion {
+				t.Errorf("expected engine version %q, but got %q", tc.engineVersion, ua.EngineVersion())
+			}
+		})
+	}
+}
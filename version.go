@@ -0,0 +1,161 @@
+package useragent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a dotted numeric version with any number of components, such as
+// "10", "17.2" or "120.0.6099.144". Versions are compared numerically, so
+// "9" is older than "10" and missing components count as zero. The zero
+// Version is empty and older than every other version.
+//
+// Versions can be compared with ==, which also compares how they are
+// written: "10" and "10.0" are different but Compare returns 0.
+type Version struct {
+	version string
+}
+
+// ParseVersion parses a dotted numeric version.
+func ParseVersion(s string) (Version, error) {
+	if s == "" {
+		return Version{}, fmt.Errorf("%w: empty version", ErrInvalidVersion)
+	}
+
+	for component := range strings.SplitSeq(s, ".") {
+		if _, err := strconv.ParseUint(component, 10, 31); err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+	}
+
+	return Version{version: s}, nil
+}
+
+// MustParseVersion is like ParseVersion but panics if s is not a valid
+// version. It simplifies the initialization of variables holding versions.
+func MustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// parseVersion parses s as a whole, ignoring a trailing dot, and returns the
+// zero Version if it is not a valid version. Callers extract the version
+// from the user agent first.
+func parseVersion(s string) Version {
+	v, err := ParseVersion(strings.TrimSuffix(s, "."))
+	if err != nil {
+		return Version{}
+	}
+
+	return v
+}
+
+// String returns the version as it was written, or an empty string for the
+// zero Version.
+func (v Version) String() string {
+	return v.version
+}
+
+// IsZero returns true for the zero Version, which user agents without a
+// version have.
+func (v Version) IsZero() bool {
+	return v.version == ""
+}
+
+// Major returns the first component of the version, or 0 for the zero
+// Version.
+func (v Version) Major() int {
+	return v.component(0)
+}
+
+// Minor returns the second component of the version, or 0 if there is none.
+func (v Version) Minor() int {
+	return v.component(1)
+}
+
+// Compare returns -1 if v is older than other, 1 if it is newer and 0 if
+// both are the same version.
+func (v Version) Compare(other Version) int {
+	if v.IsZero() || other.IsZero() {
+		switch {
+		case v.IsZero() && other.IsZero():
+			return 0
+		case v.IsZero():
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	return compareVersions(v.version, other.version)
+}
+
+// AtLeast returns true if v is the same version as other or newer.
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// component returns the component at index i, or 0 if there is none.
+func (v Version) component(i int) int {
+	for component := range strings.SplitSeq(v.version, ".") {
+		if i == 0 {
+			n, _ := strconv.Atoi(component)
+
+			return n
+		}
+
+		i--
+	}
+
+	return 0
+}
+
+// compareVersions compares two dotted numeric versions component by
+// component, with missing components counting as zero. Non-numeric
+// components count as zero as well.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		if x != y {
+			if x < y {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// versionHasPrefix returns true if the leading components of version are
+// prefix, so "15.6.1" has the prefix "15" and "15.6" but not "15.60".
+func versionHasPrefix(version, prefix string) bool {
+	vs, ps := strings.Split(version, "."), strings.Split(prefix, ".")
+	if len(ps) > len(vs) {
+		return compareVersions(version, prefix) == 0
+	}
+
+	for i, p := range ps {
+		if compareVersions(vs[i], p) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package useragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"10", "17.2", "120.0.6099.144", "1.2.3.4.5.6"} {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", s, err)
+		}
+
+		if v.String() != s {
+			t.Errorf("expected %q, but got %q", s, v)
+		}
+	}
+
+	for _, s := range []string{"", "1.", ".1", "1..2", "1.2b", "v1", "-1", "99999999999"} {
+		if _, err := ParseVersion(s); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("expected ErrInvalidVersion for %q, but got %v", s, err)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"9", "10", -1},
+		{"10", "9", 1},
+		{"10.0", "10", 0},
+		{"15.6.1", "15.6", 1},
+		{"141.0.7390.122", "141.0.7390.96", 1},
+		{"", "0", -1},
+		{"", "", 0},
+	}
+
+	for _, tc := range testCases {
+		a, b := parseVersion(tc.a), parseVersion(tc.b)
+		if got := a.Compare(b); got != tc.want {
+			t.Errorf("expected %q compared to %q to be %d, but got %d", tc.a, tc.b, tc.want, got)
+		}
+
+		if a.AtLeast(b) != (tc.want >= 0) {
+			t.Errorf("expected %q at least %q to be %t", tc.a, tc.b, tc.want >= 0)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"9", "10", -1},
+		{"10.0", "10", 0},
+		{"15.6.1", "15.6", 1},
+		{"141.0.7390.122", "141.0.7390.96", 1},
+	}
+
+	for _, tc := range testCases {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("expected compareVersions(%q, %q) to be %d, but got %d", tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestVersionComponents(t *testing.T) {
+	t.Parallel()
+
+	v := MustParseVersion("120.3.6099.144")
+	if v.Major() != 120 || v.Minor() != 3 {
+		t.Errorf("expected 120.3, but got %d.%d", v.Major(), v.Minor())
+	}
+
+	v = MustParseVersion("14")
+	if v.Major() != 14 || v.Minor() != 0 {
+		t.Errorf("expected 14.0, but got %d.%d", v.Major(), v.Minor())
+	}
+
+	var zero Version
+	if !zero.IsZero() || zero.Major() != 0 || zero.String() != "" {
+		t.Errorf("expected the zero Version to be empty, but got %q", zero)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustParseVersion to panic")
+		}
+	}()
+
+	MustParseVersion("invalid")
+}